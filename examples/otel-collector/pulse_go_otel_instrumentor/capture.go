@@ -0,0 +1,63 @@
+package pulse_otel
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// captureHeaders adds one http.<direction>.header.<name> attribute per name
+// in names that is present in header, applying redact (if non-nil) to each
+// value. Names are matched case-insensitively.
+func captureHeaders(span trace.Span, direction string, header http.Header, names []string, redact func(name, value string) string) {
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if redact != nil {
+			redacted := make([]string, len(values))
+			for i, v := range values {
+				redacted[i] = redact(name, v)
+			}
+			values = redacted
+		}
+		span.SetAttributes(attribute.StringSlice("http."+direction+".header."+strings.ToLower(name), values))
+	}
+}
+
+// captureQueryParams adds one http.request.query.<name> attribute per name
+// in names that is present in the request's query string.
+func captureQueryParams(span trace.Span, query map[string][]string, names []string) {
+	for _, name := range names {
+		if values, ok := query[name]; ok && len(values) > 0 {
+			span.SetAttributes(attribute.StringSlice("http.request.query."+name, values))
+		}
+	}
+}
+
+// captureRequestBody reads all of r.Body, restores the body so the
+// downstream handler still sees the full payload (mirroring
+// extractProjectIDFromBody), and records its first maxBytes bytes as the
+// http.request.body attribute.
+func captureRequestBody(span trace.Span, r *http.Request, maxBytes int) {
+	if maxBytes <= 0 || r.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	truncated := body
+	if len(truncated) > maxBytes {
+		truncated = truncated[:maxBytes]
+	}
+	span.SetAttributes(attribute.String("http.request.body", string(truncated)))
+}
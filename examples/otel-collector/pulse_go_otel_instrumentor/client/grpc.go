@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultProjectMetadataKey is the gRPC metadata key GRPCInstrumentor reads
+// the project ID from, analogous to the "singlestore-project-id" header
+// HTTPMiddleware reads on the HTTP side.
+const DefaultProjectMetadataKey = "project-id"
+
+// GRPCInstrumentor builds gRPC interceptors that resolve the per-project
+// tracer provider from call metadata the same way HTTPMiddleware resolves
+// it from request headers, then delegate to otelgrpc.
+type GRPCInstrumentor struct {
+	traceManager       *pulse_otel.PulseTraceManager
+	projectMetadataKey string
+}
+
+// NewGRPCInstrumentor creates a GRPCInstrumentor backed by traceManager,
+// typically obtained via HTTPMiddleware.GetPulseTraceManager() so gRPC and
+// HTTP traffic share the same per-project providers.
+func NewGRPCInstrumentor(traceManager *pulse_otel.PulseTraceManager) *GRPCInstrumentor {
+	return &GRPCInstrumentor{
+		traceManager:       traceManager,
+		projectMetadataKey: DefaultProjectMetadataKey,
+	}
+}
+
+// WithProjectMetadataKey overrides the default "project-id" metadata key.
+func (g *GRPCInstrumentor) WithProjectMetadataKey(key string) *GRPCInstrumentor {
+	g.projectMetadataKey = key
+	return g
+}
+
+// tracerProvider resolves the project-scoped tracer provider for ctx,
+// creating one for a project ID not seen before (as HTTPMiddleware does),
+// and falls back to the global tracer provider when no project ID is
+// present in metadata or the provider can't be created.
+func (g *GRPCInstrumentor) tracerProvider(ctx context.Context) trace.TracerProvider {
+	projectID := g.projectIDFromContext(ctx)
+	if projectID == "" {
+		return otel.GetTracerProvider()
+	}
+
+	provider, err := g.traceManager.GetTracerProvider(projectID)
+	if err != nil {
+		return otel.GetTracerProvider()
+	}
+	return provider.TraceProvider()
+}
+
+func (g *GRPCInstrumentor) projectIDFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(g.projectMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if values := md.Get(g.projectMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// UnaryClientInterceptor returns a per-project unary client interceptor.
+func (g *GRPCInstrumentor) UnaryClientInterceptor(opts ...otelgrpc.Option) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		allOpts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(g.tracerProvider(ctx))}, opts...)
+		return otelgrpc.UnaryClientInterceptor(allOpts...)(ctx, method, req, reply, cc, invoker, callOpts...)
+	}
+}
+
+// StreamClientInterceptor returns a per-project streaming client interceptor.
+func (g *GRPCInstrumentor) StreamClientInterceptor(opts ...otelgrpc.Option) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		allOpts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(g.tracerProvider(ctx))}, opts...)
+		return otelgrpc.StreamClientInterceptor(allOpts...)(ctx, desc, cc, method, streamer, callOpts...)
+	}
+}
+
+// UnaryServerInterceptor returns a per-project unary server interceptor.
+func (g *GRPCInstrumentor) UnaryServerInterceptor(opts ...otelgrpc.Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		allOpts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(g.tracerProvider(ctx))}, opts...)
+		return otelgrpc.UnaryServerInterceptor(allOpts...)(ctx, req, info, handler)
+	}
+}
+
+// StreamServerInterceptor returns a per-project streaming server interceptor.
+func (g *GRPCInstrumentor) StreamServerInterceptor(opts ...otelgrpc.Option) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		allOpts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(g.tracerProvider(ss.Context()))}, opts...)
+		return otelgrpc.StreamServerInterceptor(allOpts...)(srv, ss, info, handler)
+	}
+}
@@ -0,0 +1,134 @@
+// Package client provides outbound instrumentation helpers for Go client
+// stacks that HTTPMiddleware doesn't cover directly: go-openapi generated
+// clients and gRPC.
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OpenTelemetryOption configures an openAPITransport.
+type OpenTelemetryOption func(*openAPITransport)
+
+// WithPeerInfo attaches static peer.service / net.peer.name / net.peer.port
+// attributes to every span. go-openapi's ClientOperation doesn't expose the
+// resolved host, so callers that know it (most generated clients target a
+// single host) can supply it here.
+func WithPeerInfo(service, host string, port int) OpenTelemetryOption {
+	return func(t *openAPITransport) {
+		t.peerService = service
+		t.peerHost = host
+		t.peerPort = port
+	}
+}
+
+// openAPITransport wraps a runtime.ClientTransport with a client span per
+// operation, mirroring InstrumentedTransport for plain net/http calls.
+type openAPITransport struct {
+	next         runtime.ClientTransport
+	traceManager *pulse_otel.PulseTraceManager
+	peerService  string
+	peerHost     string
+	peerPort     int
+}
+
+// NewOpenAPIRuntime wraps next (typically a *httptransport.Runtime) so every
+// submitted operation gets a client span tagged with http.method,
+// peer.service, net.peer.name/port, and W3C trace context injected into the
+// operation's outgoing headers. traceManager is typically obtained via
+// HTTPMiddleware.GetPulseTraceManager() so the span lands on the same
+// per-project tracer as the request that triggered this operation; the
+// project ID is read from operation.Context (see
+// pulse_otel.ContextWithProjectID).
+func NewOpenAPIRuntime(next runtime.ClientTransport, traceManager *pulse_otel.PulseTraceManager, opts ...OpenTelemetryOption) runtime.ClientTransport {
+	t := &openAPITransport{next: next, traceManager: traceManager}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// tracer resolves the project-scoped tracer for ctx, creating a provider for
+// a project ID not seen before (as HTTPMiddleware does), and falls back to
+// the global tracer provider when no project ID is present on ctx or the
+// provider can't be created.
+func (t *openAPITransport) tracer(ctx context.Context) trace.Tracer {
+	projectID := pulse_otel.ProjectIDFromContext(ctx)
+	if projectID == "" {
+		return otel.Tracer("openapi-client")
+	}
+
+	provider, err := t.traceManager.GetTracerProvider(projectID)
+	if err != nil {
+		return otel.Tracer("openapi-client")
+	}
+	return provider.TraceProvider().Tracer("openapi-client")
+}
+
+// Submit implements runtime.ClientTransport.
+func (t *openAPITransport) Submit(operation *runtime.ClientOperation) (interface{}, error) {
+	ctx := operation.Context
+
+	tracer := t.tracer(ctx)
+	ctx, span := tracer.Start(ctx, operation.ID,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", operation.Method),
+			attribute.String("http.route", operation.PathPattern),
+		),
+	)
+	defer span.End()
+
+	if t.peerService != "" {
+		span.SetAttributes(attribute.String("peer.service", t.peerService))
+	}
+	if t.peerHost != "" {
+		span.SetAttributes(attribute.String("net.peer.name", t.peerHost))
+	}
+	if t.peerPort != 0 {
+		span.SetAttributes(attribute.Int("net.peer.port", t.peerPort))
+	}
+
+	operation.Context = ctx
+
+	// Wrap the operation's existing param writer so trace context headers
+	// are injected alongside whatever headers the generated client already
+	// sets, without disturbing the rest of the request.
+	originalParams := operation.Params
+	operation.Params = runtime.ClientRequestWriterFunc(func(req runtime.ClientRequest, reg strfmt.Registry) error {
+		if originalParams != nil {
+			if err := originalParams.WriteToRequest(req, reg); err != nil {
+				return err
+			}
+		}
+		carrier := propagation.HeaderCarrier(make(http.Header))
+		otel.GetTextMapPropagator().Inject(ctx, carrier)
+		for key, values := range carrier {
+			if err := req.SetHeaderParam(key, values...); err != nil {
+				return fmt.Errorf("failed to inject trace header %s: %w", key, err)
+			}
+		}
+		return nil
+	})
+
+	result, err := t.next.Submit(operation)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return result, err
+	}
+
+	span.SetStatus(codes.Ok, "")
+	return result, nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
@@ -21,6 +20,57 @@ type Config struct {
 	Headers            map[string]string
 	Timeout            time.Duration
 	ResourceAttributes map[string]string
+
+	// DefaultSampling is used for any project without an entry in
+	// ProjectSampling. Defaults to SamplerAlways when left zero-valued.
+	DefaultSampling SamplingConfig
+	// ProjectSampling overrides DefaultSampling on a per-project basis,
+	// keyed by project ID.
+	ProjectSampling map[string]SamplingConfig
+
+	// TailSamplingPolicies, when non-empty, enables tail-based sampling:
+	// spans for a trace are buffered in memory until the root span ends,
+	// then forwarded to the exporter only if at least one policy votes
+	// to keep the trace.
+	TailSamplingPolicies []TailPolicy
+
+	// CapturedRequestHeaders and CapturedResponseHeaders list header names
+	// (matched case-insensitively) to record as http.request.header.<name>
+	// / http.response.header.<name> span attributes.
+	CapturedRequestHeaders  []string
+	CapturedResponseHeaders []string
+	// CapturedQueryParams lists query string parameter names to record as
+	// http.request.query.<name> span attributes.
+	CapturedQueryParams []string
+	// CaptureRequestBodyMaxBytes, when positive, records up to that many
+	// bytes of the request body as the http.request.body span attribute.
+	CaptureRequestBodyMaxBytes int
+	// HeaderRedactor, when set, is applied to every captured header value
+	// before it is attached to a span, letting callers mask secrets.
+	HeaderRedactor func(name, value string) string
+
+	// Logger receives the diagnostics HTTPMiddleware and InstrumentedTransport
+	// used to print to stdout. Defaults to a no-op logger.
+	Logger Logger
+
+	// MaxProjects caps how many per-project trace providers PulseTraceManager
+	// keeps alive at once. When the cap is exceeded, the least-recently-used
+	// project's provider is shut down and evicted. Zero means unbounded.
+	MaxProjects int
+	// ProjectIdleTTL evicts a project's provider once it hasn't been used for
+	// this long, freeing its exporter. Zero means projects are never evicted
+	// for idleness.
+	ProjectIdleTTL time.Duration
+	// ReachabilityCheckInterval controls how often the background loop
+	// re-probes each known project's collector endpoint. Defaults to 15s.
+	ReachabilityCheckInterval time.Duration
+
+	// Exporters lists the destinations a project's spans should be exported
+	// to. When empty, a single OTLP-HTTP exporter pointed at the local
+	// collector is used, matching prior behavior. When more than one entry
+	// is given, PulseTraceManager fans spans out to all of them via a
+	// TeeSpanProcessor.
+	Exporters []ExporterConfig
 }
 
 // DefaultConfig returns a default configuration
@@ -54,146 +104,373 @@ func (c *Config) AddHeader(key, value string) {
 type ProjectTraceProvider struct {
 	traceProvider        *trace.TracerProvider
 	collectorEndpointURL string // URL of the OTLP collector for this project
-	isCollectorReachable bool
-	mutex                sync.RWMutex // Protects isCollectorReachable
+	health               *EndpointHealth
 }
 
+const defaultReachabilityCheckInterval = 15 * time.Second
+
 // PulseTraceManager manages OpenTelemetry providers for multiple projects
 type PulseTraceManager struct {
 	projectTraceProviders map[string]*ProjectTraceProvider
+	lastAccess            map[string]time.Time
 	baseConfig            *Config
 	mutex                 sync.RWMutex
+	stopBackground        chan struct{}
+	backgroundDone        sync.WaitGroup
 }
 
-// NewPulseTraceManager creates a new pulse trace manager
+// NewPulseTraceManager creates a new pulse trace manager. A background
+// goroutine periodically re-checks collector reachability for every known
+// project and evicts providers that have exceeded ProjectIdleTTL; stop it
+// via Shutdown.
 func NewPulseTraceManager(baseConfig *Config) *PulseTraceManager {
 	if baseConfig == nil {
 		baseConfig = DefaultConfig()
 	}
 
-	return &PulseTraceManager{
+	tm := &PulseTraceManager{
 		projectTraceProviders: make(map[string]*ProjectTraceProvider),
+		lastAccess:            make(map[string]time.Time),
 		baseConfig:            baseConfig,
+		stopBackground:        make(chan struct{}),
+	}
+
+	tm.backgroundDone.Add(1)
+	go tm.backgroundLoop()
+
+	return tm
+}
+
+// backgroundLoop periodically re-checks collector reachability for every
+// known project and evicts providers that have been idle for longer than
+// ProjectIdleTTL, so neither cost is paid on the request path.
+func (tm *PulseTraceManager) backgroundLoop() {
+	defer tm.backgroundDone.Done()
+
+	interval := tm.baseConfig.ReachabilityCheckInterval
+	if interval <= 0 {
+		interval = defaultReachabilityCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tm.stopBackground:
+			return
+		case <-ticker.C:
+			tm.refreshReachability()
+			tm.evictIdleProjects()
+		}
+	}
+}
+
+// refreshReachability probes every known project's collector endpoint whose
+// EndpointHealth says a probe is due (immediately for endpoints that haven't
+// tripped their breaker, at most once per backoff interval for ones that
+// have) and records the result.
+func (tm *PulseTraceManager) refreshReachability() {
+	tm.mutex.RLock()
+	providers := make(map[string]*ProjectTraceProvider, len(tm.projectTraceProviders))
+	for projectID, provider := range tm.projectTraceProviders {
+		providers[projectID] = provider
+	}
+	tm.mutex.RUnlock()
+
+	for _, provider := range providers {
+		if provider.collectorEndpointURL == "" {
+			// No network collector configured for this project (e.g. a
+			// file or columnar-only Exporters list): nothing to probe.
+			continue
+		}
+		probeReachability(provider.health, provider.collectorEndpointURL)
+	}
+}
+
+// probeReachability dials endpoint, if health currently has a probe due, and
+// records the outcome. Shared by refreshReachability's periodic sweep and
+// createProjectTraceProvider's immediate post-creation probe.
+func probeReachability(health *EndpointHealth, endpoint string) {
+	if !health.ShouldProbe(time.Now()) {
+		return
+	}
+	if isReachable(endpoint, 3*time.Second) {
+		health.RecordSuccess()
+	} else {
+		health.RecordFailure(time.Now())
+	}
+}
+
+// evictIdleProjects shuts down and removes providers that haven't been used
+// within ProjectIdleTTL. A zero TTL disables idle eviction.
+func (tm *PulseTraceManager) evictIdleProjects() {
+	if tm.baseConfig.ProjectIdleTTL <= 0 {
+		return
+	}
+
+	now := time.Now()
+	tm.mutex.Lock()
+	var toEvict []string
+	for projectID, lastUsed := range tm.lastAccess {
+		if now.Sub(lastUsed) > tm.baseConfig.ProjectIdleTTL {
+			toEvict = append(toEvict, projectID)
+		}
+	}
+	tm.mutex.Unlock()
+
+	for _, projectID := range toEvict {
+		tm.evictProject(projectID)
+	}
+}
+
+// evictProject gracefully shuts down and removes a single project's
+// provider. Safe to call even if the project no longer exists.
+func (tm *PulseTraceManager) evictProject(projectID string) {
+	tm.mutex.Lock()
+	provider, exists := tm.projectTraceProviders[projectID]
+	if !exists {
+		tm.mutex.Unlock()
+		return
+	}
+	delete(tm.projectTraceProviders, projectID)
+	delete(tm.lastAccess, projectID)
+	tm.mutex.Unlock()
+
+	if provider.traceProvider != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = provider.traceProvider.Shutdown(shutdownCtx)
 	}
 }
 
-// IsCollectorReachable safely reads the collector reachability status
+// evictLeastRecentlyUsedLocked picks the least-recently-used project other
+// than keep once MaxProjects is exceeded, returning its ID and true; it does
+// not evict it itself, the caller does so via evictProject after releasing
+// tm.mutex. Callers must hold tm.mutex.
+func (tm *PulseTraceManager) evictLeastRecentlyUsedLocked(keep string) (evict string, ok bool) {
+	if tm.baseConfig.MaxProjects <= 0 || len(tm.projectTraceProviders) <= tm.baseConfig.MaxProjects {
+		return "", false
+	}
+
+	var oldestProjectID string
+	var oldestAccess time.Time
+	for projectID := range tm.projectTraceProviders {
+		if projectID == keep {
+			continue
+		}
+		accessedAt := tm.lastAccess[projectID]
+		if oldestProjectID == "" || accessedAt.Before(oldestAccess) {
+			oldestProjectID = projectID
+			oldestAccess = accessedAt
+		}
+	}
+
+	if oldestProjectID == "" {
+		return "", false
+	}
+	return oldestProjectID, true
+}
+
+// TraceProvider returns the underlying OpenTelemetry tracer provider for
+// this project, for callers (e.g. the client subpackage) that need to wire
+// it into third-party instrumentation such as otelgrpc.
+func (ptp *ProjectTraceProvider) TraceProvider() *trace.TracerProvider {
+	return ptp.traceProvider
+}
+
+// IsCollectorReachable reports whether this project's collector is
+// currently assumed reachable, per its EndpointHealth.
 func (ptp *ProjectTraceProvider) IsCollectorReachable() bool {
-	ptp.mutex.RLock()
-	defer ptp.mutex.RUnlock()
-	return ptp.isCollectorReachable
+	return ptp.health.IsReachable()
 }
 
-// SetCollectorReachable safely updates the collector reachability status
-func (ptp *ProjectTraceProvider) SetCollectorReachable(reachable bool) {
-	ptp.mutex.Lock()
-	defer ptp.mutex.Unlock()
-	ptp.isCollectorReachable = reachable
+// Health returns the EndpointHealth tracking this project's collector, for
+// callers that want state transitions (Health().Subscribe) or finer-grained
+// status than a bool.
+func (ptp *ProjectTraceProvider) Health() *EndpointHealth {
+	return ptp.health
 }
 
-// CheckAndUpdateCollectorReachability checks if collector is reachable and updates the status
+// CheckAndUpdateCollectorReachability returns the cached reachability status
+// for a project's collector. The actual probing happens off the request
+// path in the background loop, so this never blocks on a dial.
 func (tm *PulseTraceManager) CheckAndUpdateCollectorReachability(projectID string) (bool, error) {
 	provider, err := tm.GetTracerProvider(projectID)
 	if err != nil {
 		return false, err
 	}
 
-	// Check current status first
-	currentStatus := provider.IsCollectorReachable()
-
-	// If already marked as reachable, return without checking again to avoid overhead
-	if currentStatus {
-		return true, nil
-	}
+	return provider.IsCollectorReachable(), nil
+}
 
-	// Check actual reachability
-	isReachable := isReachable(provider.collectorEndpointURL, 3*time.Second)
+// EndpointStatus returns the current collector health state of every
+// project this manager has created a provider for, keyed by project ID, for
+// use in /health handlers.
+func (tm *PulseTraceManager) EndpointStatus() map[string]HealthState {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
 
-	// Update the status
-	provider.SetCollectorReachable(isReachable)
+	statuses := make(map[string]HealthState, len(tm.projectTraceProviders))
+	for projectID, provider := range tm.projectTraceProviders {
+		statuses[projectID] = provider.health.State()
+	}
+	return statuses
+}
 
-	return isReachable, nil
+// HasProject reports whether projectID already has a provider, without
+// creating one. Unlike GetTracerProvider, this never mutates manager state
+// (no LRU access-time bump, no provider creation), so it's safe to call from
+// a hot path like a log hook that shouldn't itself spin up providers or
+// contend with eviction.
+func (tm *PulseTraceManager) HasProject(projectID string) bool {
+	tm.mutex.RLock()
+	defer tm.mutex.RUnlock()
+	_, exists := tm.projectTraceProviders[projectID]
+	return exists
 }
 
-// GetTracerProvider returns or creates a tracer provider for a specific project
+// GetTracerProvider returns or creates a tracer provider for a specific
+// project. Creating a provider never dials the collector synchronously;
+// reachability is first probed by a goroutine started when the provider is
+// created and then re-checked by the background loop started in
+// NewPulseTraceManager.
 func (tm *PulseTraceManager) GetTracerProvider(projectID string) (*ProjectTraceProvider, error) {
 	tm.mutex.RLock()
 	provider, exists := tm.projectTraceProviders[projectID]
 	tm.mutex.RUnlock()
 
 	if exists {
+		tm.mutex.Lock()
+		tm.lastAccess[projectID] = time.Now()
+		tm.mutex.Unlock()
 		return provider, nil
 	}
 
 	tm.mutex.Lock()
-	defer tm.mutex.Unlock()
 
 	// Double-check after acquiring write lock
 	if provider, exists := tm.projectTraceProviders[projectID]; exists {
+		tm.lastAccess[projectID] = time.Now()
+		tm.mutex.Unlock()
 		return provider, nil
 	}
 
 	// Create new provider for project
 	provider, err := tm.createProjectTraceProvider(projectID)
 	if err != nil {
+		tm.mutex.Unlock()
 		return nil, fmt.Errorf("failed to create provider for project %s: %w", projectID, err)
 	}
 
 	tm.projectTraceProviders[projectID] = provider
+	tm.lastAccess[projectID] = time.Now()
+	evictID, shouldEvict := tm.evictLeastRecentlyUsedLocked(projectID)
+	tm.mutex.Unlock()
+
+	if shouldEvict {
+		tm.evictProject(evictID)
+	}
+
 	return provider, nil
 }
 
 func (tm *PulseTraceManager) createProjectTraceProvider(projectID string) (*ProjectTraceProvider, error) {
+	return newProjectTraceProvider(tm.baseConfig, projectID)
+}
+
+// newProjectTraceProvider builds a ProjectTraceProvider for projectID from
+// baseConfig. It's a standalone function (rather than a PulseTraceManager
+// method) so one-off lookups like setupGlobalOTelProviders's global default
+// provider can build a provider directly, without paying for a whole
+// manager's background eviction/reachability-sweep goroutine.
+func newProjectTraceProvider(baseConfig *Config, projectID string) (*ProjectTraceProvider, error) {
 	ctx := context.Background()
 
 	// Create project-specific resource
-	res, err := tm.createProjectTraceResource(projectID)
+	res, err := createProjectResource(baseConfig, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	// Form project-specific collector endpoint
-	// collectorEndpointURL := strings.Replace(OTEL_COLLECTOR_ENDPOINT, "{PROJECTID_PLACEHOLDER}", projectID, 1)
-	collectorEndpointURL := "otel-collector:4318"
+	// Config.Exporters lets a project fan its spans out to multiple
+	// destinations (OTLP, file, a columnar store, ...); an empty slice
+	// keeps the original single-OTLP-collector default.
+	exporterConfigs := baseConfig.Exporters
+	if len(exporterConfigs) == 0 {
+		exporterConfigs = []ExporterConfig{defaultOTLPExporterConfig(baseConfig)}
+	}
 
-	isCollectorReachable := isReachable(collectorEndpointURL, 3*time.Second)
+	// Reachability starts HealthUnknown. A probe is kicked off in the
+	// background below so it's established within a dial timeout rather
+	// than waiting for the periodic loop's next tick (up to
+	// ReachabilityCheckInterval away), while still not blocking this
+	// project's first request on a 3-second dial. Exporter configs with no
+	// network collector to dial (file, columnar) are treated as always
+	// reachable.
+	collectorEndpointURL := reachabilityEndpoint(exporterConfigs)
+	health := NewEndpointHealth(collectorEndpointURL)
+	if collectorEndpointURL == "" {
+		health.RecordSuccess()
+	} else {
+		go probeReachability(health, collectorEndpointURL)
+	}
 
-	// Create OTLP HTTP exporter for this project
-	exporter, err := otlptracehttp.New(ctx,
-		otlptracehttp.WithEndpoint(collectorEndpointURL),
-		otlptracehttp.WithHeaders(tm.baseConfig.Headers),
-		otlptracehttp.WithTimeout(tm.baseConfig.Timeout),
-		otlptracehttp.WithInsecure(),
-	)
+	processors := make([]trace.SpanProcessor, 0, len(exporterConfigs))
+	for _, exporterCfg := range exporterConfigs {
+		exporter, err := buildExporter(ctx, exporterCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s exporter for project %s: %w", exporterCfg.Kind, projectID, err)
+		}
+		processors = append(processors, trace.NewBatchSpanProcessor(exporter))
+	}
+
+	// Fan out to every configured exporter when there's more than one;
+	// otherwise use the lone processor directly.
+	var processor trace.SpanProcessor = processors[0]
+	if len(processors) > 1 {
+		processor = NewTeeSpanProcessor(processors...)
+	}
+
+	// When tail sampling policies are configured, spans are buffered per
+	// trace and only handed to the exporter(s) once the root span ends and
+	// a policy votes to keep the trace.
+	if len(baseConfig.TailSamplingPolicies) > 0 {
+		processor = newTailSamplingProcessor(processor, baseConfig.TailSamplingPolicies...)
+	}
+
+	sampler, err := buildSampler(baseConfig.resolveSamplingConfig(projectID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+		return nil, fmt.Errorf("failed to build sampler for project %s: %w", projectID, err)
 	}
 
 	// Create tracer provider
 	provider := trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
+		trace.WithSpanProcessor(processor),
 		trace.WithResource(res),
-		trace.WithSampler(trace.AlwaysSample()),
+		trace.WithSampler(sampler),
 	)
 
 	return &ProjectTraceProvider{
 		traceProvider:        provider,
-		isCollectorReachable: isCollectorReachable, // Assume reachable initially
+		health:               health,
 		collectorEndpointURL: collectorEndpointURL,
 	}, nil
 }
 
-func (tm *PulseTraceManager) createProjectTraceResource(projectID string) (*resource.Resource, error) {
-	// Start with base attributes
+// createProjectResource builds the OTel resource shared by a project's trace
+// and meter providers: base service/environment attributes, the project.id
+// that scopes everything, plus any custom resource attributes.
+func createProjectResource(baseConfig *Config, projectID string) (*resource.Resource, error) {
 	attributes := []attribute.KeyValue{
-		semconv.ServiceName(tm.baseConfig.ServiceName),
-		semconv.ServiceVersion(tm.baseConfig.ServiceVersion),
-		semconv.DeploymentEnvironment(tm.baseConfig.Environment),
+		semconv.ServiceName(baseConfig.ServiceName),
+		semconv.ServiceVersion(baseConfig.ServiceVersion),
+		semconv.DeploymentEnvironment(baseConfig.Environment),
 		attribute.String("project.id", projectID),
 	}
 
 	// Add custom resource attributes
-	for key, value := range tm.baseConfig.ResourceAttributes {
+	for key, value := range baseConfig.ResourceAttributes {
 		attributes = append(attributes, attribute.String(key, value))
 	}
 
@@ -206,8 +483,12 @@ func (tm *PulseTraceManager) createProjectTraceResource(projectID string) (*reso
 	)
 }
 
-// Shutdown gracefully shuts down all project providers
+// Shutdown stops the background reachability/eviction loop and gracefully
+// shuts down all project providers.
 func (tm *PulseTraceManager) Shutdown(ctx context.Context) error {
+	close(tm.stopBackground)
+	tm.backgroundDone.Wait()
+
 	tm.mutex.Lock()
 	defer tm.mutex.Unlock()
 
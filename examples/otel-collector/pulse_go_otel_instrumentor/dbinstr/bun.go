@@ -0,0 +1,111 @@
+package dbinstr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BunOption configures the bun.QueryHook returned by NewQueryHook.
+type BunOption func(*bunHook)
+
+// WithBunDBSystem sets the db.system attribute recorded on every span.
+// Defaults to "other_sql".
+func WithBunDBSystem(system string) BunOption {
+	return func(h *bunHook) { h.system = system }
+}
+
+// WithBunRedactedParams omits the rendered SQL statement (db.statement) from
+// query spans; db.system, db.operation, db.table, and db.rows_affected are
+// still recorded.
+func WithBunRedactedParams() BunOption {
+	return func(h *bunHook) { h.redactParams = true }
+}
+
+type bunSpanKeyType struct{}
+
+var bunSpanKey bunSpanKeyType
+
+// bunHook implements bun.QueryHook, mirroring bunotel.NewQueryHook but
+// resolving a per-project tracer from PulseTraceManager instead of a single
+// global provider.
+type bunHook struct {
+	traceManager *pulse_otel.PulseTraceManager
+	system       string
+	redactParams bool
+}
+
+// NewQueryHook returns a bun.QueryHook that records a span per query on the
+// per-project tracer resolved from the project ID on ctx (see
+// pulse_otel.ContextWithProjectID), auto-populating db.system, db.statement,
+// db.operation, db.table, and db.rows_affected. Install it via
+// db.AddQueryHook(dbinstr.NewQueryHook(traceManager)).
+func NewQueryHook(traceManager *pulse_otel.PulseTraceManager, opts ...BunOption) bun.QueryHook {
+	h := &bunHook{traceManager: traceManager, system: "other_sql"}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *bunHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	// Most bun traffic runs outside any HTTP request (background jobs,
+	// migrations) and so has no project ID on ctx; GetTracer would
+	// otherwise treat "" (or any other project ID it hasn't seen) as a
+	// brand-new project and dial an exporter for it, so HasProject gates
+	// that and the global tracer provider is used instead.
+	projectID := pulse_otel.ProjectIDFromContext(ctx)
+	tracer := pulse_otel.NewTracer("bun")
+	if projectID != "" && h.traceManager.HasProject(projectID) {
+		if projectTracer, err := h.traceManager.GetTracer(projectID, "bun"); err == nil {
+			tracer = projectTracer
+		}
+	}
+
+	operation := event.Operation()
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", h.system),
+		attribute.String("db.operation", operation),
+	}
+	if table := tableFromQuery(event.Query); table != "" {
+		attrs = append(attrs, attribute.String("db.table", table))
+	}
+
+	spanName := operation
+	if spanName == "" {
+		spanName = "bun.query"
+	}
+	ctx, span := tracer.StartSpan(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return context.WithValue(ctx, bunSpanKey, span)
+}
+
+func (h *bunHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	span, ok := ctx.Value(bunSpanKey).(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if !h.redactParams {
+		span.SetAttributes(attribute.String("db.statement", event.Query))
+	}
+	if event.Result != nil {
+		if rows, err := event.Result.RowsAffected(); err == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+		}
+	}
+
+	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+}
@@ -0,0 +1,132 @@
+package dbinstr
+
+import (
+	"context"
+	"errors"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// GormOption configures the gorm.Plugin returned by NewGormPlugin.
+type GormOption func(*gormPlugin)
+
+// WithGormDBSystem sets the db.system attribute recorded on every span.
+// Defaults to "other_sql".
+func WithGormDBSystem(system string) GormOption {
+	return func(p *gormPlugin) { p.system = system }
+}
+
+// WithGormRedactedParams omits the rendered SQL statement (db.statement)
+// from query spans; db.system, db.operation, db.table, and db.rows_affected
+// are still recorded.
+func WithGormRedactedParams() GormOption {
+	return func(p *gormPlugin) { p.redactParams = true }
+}
+
+type gormSpanKeyType struct{}
+
+var gormSpanKey gormSpanKeyType
+
+// gormPlugin implements gorm.Plugin, registering before/after callbacks for
+// each gorm operation that record a span on the per-project tracer resolved
+// from PulseTraceManager.
+type gormPlugin struct {
+	traceManager *pulse_otel.PulseTraceManager
+	system       string
+	redactParams bool
+}
+
+// NewGormPlugin returns a gorm.Plugin that records a span per
+// create/query/update/delete/row/raw callback on the per-project tracer
+// resolved from the project ID on the statement's context (see
+// pulse_otel.ContextWithProjectID), auto-populating db.system, db.statement,
+// db.operation, db.table, and db.rows_affected. Install it via
+// db.Use(dbinstr.NewGormPlugin(traceManager)).
+func NewGormPlugin(traceManager *pulse_otel.PulseTraceManager, opts ...GormOption) gorm.Plugin {
+	p := &gormPlugin{traceManager: traceManager, system: "other_sql"}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *gormPlugin) Name() string { return "pulse_otel" }
+
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	errs := []error{
+		db.Callback().Create().Before("gorm:create").Register("pulse_otel:before_create", p.before("INSERT")),
+		db.Callback().Create().After("gorm:create").Register("pulse_otel:after_create", p.after),
+		db.Callback().Query().Before("gorm:query").Register("pulse_otel:before_query", p.before("SELECT")),
+		db.Callback().Query().After("gorm:query").Register("pulse_otel:after_query", p.after),
+		db.Callback().Update().Before("gorm:update").Register("pulse_otel:before_update", p.before("UPDATE")),
+		db.Callback().Update().After("gorm:update").Register("pulse_otel:after_update", p.after),
+		db.Callback().Delete().Before("gorm:delete").Register("pulse_otel:before_delete", p.before("DELETE")),
+		db.Callback().Delete().After("gorm:delete").Register("pulse_otel:after_delete", p.after),
+		db.Callback().Row().Before("gorm:row").Register("pulse_otel:before_row", p.before("ROW")),
+		db.Callback().Row().After("gorm:row").Register("pulse_otel:after_row", p.after),
+		db.Callback().Raw().Before("gorm:raw").Register("pulse_otel:before_raw", p.before("RAW")),
+		db.Callback().Raw().After("gorm:raw").Register("pulse_otel:after_raw", p.after),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *gormPlugin) before(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		// Most gorm traffic runs outside any HTTP request (background jobs,
+		// migrations) and so has no project ID on the statement's context;
+		// GetTracer would otherwise treat "" (or any other project ID it
+		// hasn't seen) as a brand-new project and dial an exporter for it,
+		// so HasProject gates that and the global tracer provider is used
+		// instead.
+		projectID := pulse_otel.ProjectIDFromContext(tx.Statement.Context)
+		tracer := pulse_otel.NewTracer("gorm")
+		if projectID != "" && p.traceManager.HasProject(projectID) {
+			if projectTracer, err := p.traceManager.GetTracer(projectID, "gorm"); err == nil {
+				tracer = projectTracer
+			}
+		}
+
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", p.system),
+			attribute.String("db.operation", operation),
+		}
+		if tx.Statement.Table != "" {
+			attrs = append(attrs, attribute.String("db.table", tx.Statement.Table))
+		}
+
+		ctx, span := tracer.StartSpan(tx.Statement.Context, operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		tx.Statement.Context = context.WithValue(ctx, gormSpanKey, span)
+	}
+}
+
+func (p *gormPlugin) after(tx *gorm.DB) {
+	span, ok := tx.Statement.Context.Value(gormSpanKey).(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if !p.redactParams && tx.Statement.SQL.Len() > 0 {
+		span.SetAttributes(attribute.String("db.statement", tx.Statement.SQL.String()))
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", tx.Statement.RowsAffected))
+
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
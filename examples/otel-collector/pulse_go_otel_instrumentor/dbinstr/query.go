@@ -0,0 +1,42 @@
+// Package dbinstr provides ready-made OpenTelemetry hooks for common Go
+// database libraries (database/sql, bun, gorm) so instrumenting a query
+// doesn't require wrapping every call site in tracer.WithSpanReturnTyped by
+// hand. Each hook resolves its tracer from a PulseTraceManager using the
+// project ID stashed on the query's context by HTTPMiddleware (or explicitly
+// via pulse_otel.ContextWithProjectID), so database spans land on the same
+// per-project provider as the request that triggered them.
+package dbinstr
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tableNameRe matches the table name following FROM/INTO/UPDATE/JOIN in a
+// SQL statement. Best-effort: it's meant to populate db.table for simple
+// queries, not to parse arbitrary SQL.
+var tableNameRe = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+"?([a-zA-Z0-9_\.]+)"?`)
+
+// operationFromQuery returns the statement's leading verb (SELECT, INSERT,
+// UPDATE, DELETE, ...) upper-cased, for use as the db.operation attribute.
+func operationFromQuery(query string) string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return ""
+	}
+	end := strings.IndexFunc(query, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' })
+	if end == -1 {
+		end = len(query)
+	}
+	return strings.ToUpper(query[:end])
+}
+
+// tableFromQuery returns the first table name referenced by query, or "" if
+// none could be found.
+func tableFromQuery(query string) string {
+	match := tableNameRe.FindStringSubmatch(query)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
@@ -0,0 +1,239 @@
+package dbinstr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SQLOption configures the database/sql driver wrapper registered by Register.
+type SQLOption func(*sqlConfig)
+
+type sqlConfig struct {
+	system       string
+	redactParams bool
+}
+
+// WithDBSystem sets the db.system attribute recorded on every span (e.g.
+// "mysql", "postgresql"). Defaults to "other_sql".
+func WithDBSystem(system string) SQLOption {
+	return func(c *sqlConfig) { c.system = system }
+}
+
+// WithRedactedParams omits the SQL statement (db.statement) from query
+// spans, recording the argument count (db.params.count) in its place, for
+// queries whose text may contain sensitive data. Bound parameter values are
+// never recorded, with or without this option.
+func WithRedactedParams() SQLOption {
+	return func(c *sqlConfig) { c.redactParams = true }
+}
+
+// Register wraps driverImpl with span-producing instrumentation and
+// registers it under name via sql.Register, so sql.Open(name, dsn) returns
+// an instrumented *sql.DB. traceManager is typically obtained via
+// HTTPMiddleware.GetPulseTraceManager() so database spans land on the same
+// per-project tracer as the request that triggered them; the project ID is
+// read from the context.Context passed to each query (see
+// pulse_otel.ContextWithProjectID).
+func Register(name string, driverImpl driver.Driver, traceManager *pulse_otel.PulseTraceManager, opts ...SQLOption) {
+	cfg := &sqlConfig{system: "other_sql"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	sql.Register(name, &otelDriver{next: driverImpl, traceManager: traceManager, cfg: cfg})
+}
+
+type otelDriver struct {
+	next         driver.Driver
+	traceManager *pulse_otel.PulseTraceManager
+	cfg          *sqlConfig
+}
+
+func (d *otelDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.next.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &otelConn{next: conn, traceManager: d.traceManager, cfg: d.cfg}, nil
+}
+
+type otelConn struct {
+	next         driver.Conn
+	traceManager *pulse_otel.PulseTraceManager
+	cfg          *sqlConfig
+}
+
+func (c *otelConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.next.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &otelStmt{next: stmt, traceManager: c.traceManager, cfg: c.cfg, query: query}, nil
+}
+
+func (c *otelConn) Close() error { return c.next.Close() }
+
+func (c *otelConn) Begin() (driver.Tx, error) { return c.next.Begin() } //nolint:staticcheck // driver.Conn still requires it
+
+// PrepareContext implements driver.ConnPrepareContext when the wrapped
+// driver supports it.
+func (c *otelConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	prep, ok := c.next.(driver.ConnPrepareContext)
+	if !ok {
+		return c.Prepare(query)
+	}
+	stmt, err := prep.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &otelStmt{next: stmt, traceManager: c.traceManager, cfg: c.cfg, query: query}, nil
+}
+
+// ExecContext implements driver.ExecerContext when the wrapped driver
+// supports it, recording a span around the exec.
+func (c *otelConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.next.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startSpan(ctx, c.traceManager, c.cfg, "exec", query, len(args))
+	defer span.End()
+
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if rows, rowsErr := result.RowsAffected(); rowsErr == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+	}
+	return result, nil
+}
+
+// QueryContext implements driver.QueryerContext when the wrapped driver
+// supports it, recording a span around the query.
+func (c *otelConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.next.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startSpan(ctx, c.traceManager, c.cfg, "query", query, len(args))
+	defer span.End()
+
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return rows, nil
+}
+
+type otelStmt struct {
+	next         driver.Stmt
+	traceManager *pulse_otel.PulseTraceManager
+	cfg          *sqlConfig
+	query        string
+}
+
+func (s *otelStmt) Close() error  { return s.next.Close() }
+func (s *otelStmt) NumInput() int { return s.next.NumInput() }
+
+func (s *otelStmt) Exec(args []driver.Value) (driver.Result, error) { //nolint:staticcheck
+	return s.next.Exec(args) //nolint:staticcheck
+}
+
+func (s *otelStmt) Query(args []driver.Value) (driver.Rows, error) { //nolint:staticcheck
+	return s.next.Query(args) //nolint:staticcheck
+}
+
+// ExecContext implements driver.StmtExecContext when the wrapped statement
+// supports it, recording a span around the exec.
+func (s *otelStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := s.next.(driver.StmtExecContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startSpan(ctx, s.traceManager, s.cfg, "exec", s.query, len(args))
+	defer span.End()
+
+	result, err := execer.ExecContext(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	if rows, rowsErr := result.RowsAffected(); rowsErr == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+	}
+	return result, nil
+}
+
+// QueryContext implements driver.StmtQueryContext when the wrapped statement
+// supports it, recording a span around the query.
+func (s *otelStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := s.next.(driver.StmtQueryContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := startSpan(ctx, s.traceManager, s.cfg, "query", s.query, len(args))
+	defer span.End()
+
+	rows, err := queryer.QueryContext(ctx, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return rows, nil
+}
+
+// startSpan resolves the per-project tracer for ctx and starts a client span
+// for a database/sql operation, auto-populating db.system, db.statement,
+// db.operation, and db.table. Most database/sql traffic runs outside any
+// HTTP request (background jobs, cron, migrations) and so has no project ID
+// on ctx; traceManager.GetTracer would otherwise treat "" (or any other
+// project ID it hasn't seen) as a brand-new project and dial an exporter for
+// it, so HasProject gates that and the global tracer provider is used
+// instead, the same fallback client/grpc.go and client/openapi.go use.
+func startSpan(ctx context.Context, traceManager *pulse_otel.PulseTraceManager, cfg *sqlConfig, spanKind, query string, argCount int) (context.Context, trace.Span) {
+	projectID := pulse_otel.ProjectIDFromContext(ctx)
+
+	tracer := pulse_otel.NewTracer("database/sql")
+	if projectID != "" && traceManager.HasProject(projectID) {
+		if projectTracer, err := traceManager.GetTracer(projectID, "database/sql"); err == nil {
+			tracer = projectTracer
+		}
+	}
+
+	operation := operationFromQuery(query)
+	spanName := operation
+	if spanName == "" {
+		spanName = "sql." + spanKind
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.system", cfg.system),
+		attribute.String("db.operation", operation),
+	}
+	if table := tableFromQuery(query); table != "" {
+		attrs = append(attrs, attribute.String("db.table", table))
+	}
+	if cfg.redactParams {
+		attrs = append(attrs, attribute.Int("db.params.count", argCount))
+	} else {
+		attrs = append(attrs, attribute.String("db.statement", query))
+	}
+
+	return tracer.StartSpan(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,225 @@
+package pulse_otel
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// HealthState describes an EndpointHealth's current assessment of whether an
+// endpoint is reachable.
+type HealthState int
+
+const (
+	// HealthUnknown is the state before any probe has succeeded or the
+	// circuit breaker has tripped; failed probes below the failure
+	// threshold leave it unchanged.
+	HealthUnknown HealthState = iota
+	// HealthUp means a probe has succeeded and the circuit breaker hasn't
+	// tripped since; failed probes below the failure threshold leave it
+	// unchanged.
+	HealthUp
+	// HealthDown means the circuit breaker has tripped after consecutive
+	// failed probes; the endpoint is assumed unreachable until a probe
+	// succeeds.
+	HealthDown
+)
+
+// String implements fmt.Stringer for use in logs and /health responses.
+func (s HealthState) String() string {
+	switch s {
+	case HealthUp:
+		return "up"
+	case HealthDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	defaultInitialBackoff   = 3 * time.Second
+	defaultMaxBackoff       = 30 * time.Second
+	defaultFailureThreshold = 3
+)
+
+// EndpointHealth tracks the reachability of a single collector endpoint with
+// an exponential backoff between probes and a half-open circuit breaker, so
+// a down collector isn't dialed on every check and callers don't block
+// behind a full dial timeout once it's known to be down.
+type EndpointHealth struct {
+	endpoint         string
+	failureThreshold int
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+
+	mutex               sync.Mutex
+	state               HealthState
+	consecutiveFailures int
+	backoff             time.Duration
+	nextProbeAt         time.Time
+	probeInFlight       bool
+	subscribers         []chan HealthState
+}
+
+// EndpointHealthOption configures an EndpointHealth via functional options.
+type EndpointHealthOption func(*EndpointHealth)
+
+// WithFailureThreshold sets how many consecutive failed probes trip the
+// circuit breaker into HealthDown. Defaults to 3.
+func WithFailureThreshold(n int) EndpointHealthOption {
+	return func(h *EndpointHealth) { h.failureThreshold = n }
+}
+
+// WithBackoffRange overrides the default 3s-initial/30s-max exponential
+// backoff between probes of a down endpoint.
+func WithBackoffRange(initial, max time.Duration) EndpointHealthOption {
+	return func(h *EndpointHealth) {
+		h.initialBackoff = initial
+		h.maxBackoff = max
+	}
+}
+
+// NewEndpointHealth creates an EndpointHealth for endpoint, starting in
+// HealthUnknown until the first probe completes.
+func NewEndpointHealth(endpoint string, opts ...EndpointHealthOption) *EndpointHealth {
+	h := &EndpointHealth{
+		endpoint:         endpoint,
+		failureThreshold: defaultFailureThreshold,
+		initialBackoff:   defaultInitialBackoff,
+		maxBackoff:       defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.backoff = h.initialBackoff
+	return h
+}
+
+// State returns the endpoint's last-known health state.
+func (h *EndpointHealth) State() HealthState {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.state
+}
+
+// IsReachable reports whether the endpoint is currently assumed reachable.
+func (h *EndpointHealth) IsReachable() bool {
+	return h.State() == HealthUp
+}
+
+// ShouldProbe reports whether a probe should be attempted now: always true
+// before the breaker has tripped, and while tripped, true only once per
+// backoff interval (the half-open check), so a down collector isn't dialed
+// on every tick. Callers that get true must follow up with RecordSuccess or
+// RecordFailure once the probe completes.
+func (h *EndpointHealth) ShouldProbe(now time.Time) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.state != HealthDown {
+		return true
+	}
+	if h.probeInFlight || now.Before(h.nextProbeAt) {
+		return false
+	}
+	h.probeInFlight = true
+	return true
+}
+
+// RecordSuccess marks the endpoint reachable, resets the breaker, and
+// notifies subscribers if this is a state transition.
+func (h *EndpointHealth) RecordSuccess() {
+	h.mutex.Lock()
+	h.probeInFlight = false
+	h.consecutiveFailures = 0
+	h.backoff = h.initialBackoff
+	changed := h.state != HealthUp
+	h.state = HealthUp
+	h.mutex.Unlock()
+
+	if changed {
+		h.notify(HealthUp)
+	}
+}
+
+// RecordFailure records a failed probe, tripping the breaker once
+// consecutive failures reach failureThreshold, and schedules the next
+// half-open probe after a jittered exponential backoff.
+func (h *EndpointHealth) RecordFailure(now time.Time) {
+	h.mutex.Lock()
+	h.probeInFlight = false
+	h.consecutiveFailures++
+
+	var changed bool
+	if h.consecutiveFailures >= h.failureThreshold {
+		changed = h.state != HealthDown
+		h.state = HealthDown
+		h.nextProbeAt = now.Add(jitter(h.backoff))
+		h.backoff *= 2
+		if h.backoff > h.maxBackoff {
+			h.backoff = h.maxBackoff
+		}
+	}
+	h.mutex.Unlock()
+
+	if changed {
+		h.notify(HealthDown)
+	}
+}
+
+// jitter returns d plus up to 20% random jitter, so endpoints sharing the
+// same backoff schedule don't all probe in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/5+1))
+}
+
+// Subscribe returns a channel that receives every subsequent state
+// transition, so exporters and the project manager can react without
+// polling State(). The channel is closed once ctx is done; callers should
+// keep draining it in the meantime, since sends are non-blocking and a
+// subscriber whose one-slot buffer is still full misses that transition
+// (other subscribers are unaffected). Removal and closing happen under the same mutex notify sends
+// under, so notify can never observe (and send on) a channel the ctx.Done
+// goroutine is in the middle of closing.
+func (h *EndpointHealth) Subscribe(ctx context.Context) <-chan HealthState {
+	ch := make(chan HealthState, 1)
+
+	h.mutex.Lock()
+	h.subscribers = append(h.subscribers, ch)
+	h.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		for i, sub := range h.subscribers {
+			if sub == ch {
+				h.subscribers = append(h.subscribers[:i], h.subscribers[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch
+}
+
+// notify sends state to every subscriber while holding h.mutex for the
+// whole loop, so it can't race with Subscribe's ctx.Done goroutine removing
+// and closing a channel concurrently (both serialize on the same mutex).
+func (h *EndpointHealth) notify(state HealthState) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for _, sub := range h.subscribers {
+		select {
+		case sub <- state:
+		default:
+		}
+	}
+}
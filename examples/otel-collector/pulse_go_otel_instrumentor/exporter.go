@@ -0,0 +1,303 @@
+package pulse_otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExporterKind identifies which built-in ExporterFactory an ExporterConfig
+// should be built with.
+type ExporterKind string
+
+const (
+	// ExporterOTLPHTTP exports via OTLP over HTTP, the module's original
+	// (and still default) behavior.
+	ExporterOTLPHTTP ExporterKind = "otlp-http"
+	// ExporterOTLPGRPC exports via OTLP over gRPC.
+	ExporterOTLPGRPC ExporterKind = "otlp-grpc"
+	// ExporterFile writes one JSON line per span to a file or stdout, for
+	// local development without a running collector.
+	ExporterFile ExporterKind = "file"
+	// ExporterColumnar writes one row per span to a columnar store (e.g.
+	// Cassandra or ClickHouse) via a caller-supplied ColumnarWriter.
+	ExporterColumnar ExporterKind = "columnar"
+)
+
+// ExporterConfig describes one destination a project's spans should be
+// exported to. Only the fields relevant to Kind need to be set.
+type ExporterConfig struct {
+	Kind ExporterKind
+
+	// Endpoint is the collector address for ExporterOTLPHTTP/ExporterOTLPGRPC.
+	Endpoint string
+	// Headers are attached to every OTLP export request.
+	Headers map[string]string
+	// Insecure disables TLS for OTLP exporters. defaultOTLPExporterConfig
+	// sets it to true, matching this package's assumption of a local
+	// collector; an ExporterConfig that leaves it unset keeps TLS enabled.
+	Insecure bool
+	// Timeout bounds OTLP export calls. Defaults from Config.Timeout in
+	// defaultOTLPExporterConfig; zero means the SDK's own default.
+	Timeout time.Duration
+
+	// Writer is where ExporterFile writes one JSON line per span. Defaults
+	// to os.Stdout when nil.
+	Writer io.Writer
+
+	// ColumnarWriter receives one row per span for ExporterColumnar,
+	// typically backed by a Cassandra or ClickHouse client.
+	ColumnarWriter ColumnarWriter
+	// Keyspace (Cassandra) or database (ClickHouse) the ColumnarWriter
+	// should target.
+	Keyspace string
+	// TTL sets the row expiry a columnar exporter should apply, if its
+	// ColumnarWriter honors it. Zero means no expiry.
+	TTL time.Duration
+}
+
+// ExporterFactory builds a sdktrace.SpanExporter from an ExporterConfig.
+// RegisterExporterFactory lets callers add a factory for a custom
+// ExporterKind alongside the built-in ones.
+type ExporterFactory func(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error)
+
+var exporterFactories = map[ExporterKind]ExporterFactory{
+	ExporterOTLPHTTP: newOTLPHTTPExporter,
+	ExporterOTLPGRPC: newOTLPGRPCExporter,
+	ExporterFile:     newFileExporter,
+	ExporterColumnar: newColumnarExporter,
+}
+
+// RegisterExporterFactory adds or overrides the factory used to build
+// exporters of the given kind.
+func RegisterExporterFactory(kind ExporterKind, factory ExporterFactory) {
+	exporterFactories[kind] = factory
+}
+
+func buildExporter(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	factory, ok := exporterFactories[cfg.Kind]
+	if !ok {
+		return nil, fmt.Errorf("no exporter factory registered for kind %q", cfg.Kind)
+	}
+	return factory(ctx, cfg)
+}
+
+// defaultOTLPExporterConfig returns the ExporterConfig used when Config.Exporters
+// is left empty, preserving the module's original single-local-collector default.
+func defaultOTLPExporterConfig(cfg *Config) ExporterConfig {
+	return ExporterConfig{
+		Kind:     ExporterOTLPHTTP,
+		Endpoint: "otel-collector:4318",
+		Headers:  cfg.Headers,
+		Insecure: true,
+		Timeout:  cfg.Timeout,
+	}
+}
+
+// reachabilityEndpoint returns the host:port EndpointHealth should probe for
+// a project: the first OTLP exporter's endpoint, or "" if none of the
+// configured exporters talk to a network collector (e.g. file or columnar
+// only, which are always considered reachable).
+func reachabilityEndpoint(exporterConfigs []ExporterConfig) string {
+	for _, cfg := range exporterConfigs {
+		if (cfg.Kind == ExporterOTLPHTTP || cfg.Kind == ExporterOTLPGRPC) && cfg.Endpoint != "" {
+			return cfg.Endpoint
+		}
+	}
+	return ""
+}
+
+func newOTLPHTTPExporter(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+		otlptracehttp.WithHeaders(cfg.Headers),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(cfg.Timeout))
+	}
+	return otlptracehttp.New(ctx, opts...)
+}
+
+func newOTLPGRPCExporter(ctx context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithHeaders(cfg.Headers),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(cfg.Timeout))
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// fileSpanExporter writes one JSON line per span to writer, for local
+// development without a running collector.
+type fileSpanExporter struct {
+	writer io.Writer
+}
+
+func newFileExporter(_ context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	writer := cfg.Writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+	return &fileSpanExporter{writer: writer}, nil
+}
+
+type fileSpanRecord struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	StartTime    time.Time         `json:"start_time"`
+	EndTime      time.Time         `json:"end_time"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	StatusCode   string            `json:"status_code"`
+}
+
+func (e *fileSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		record := fileSpanRecord{
+			TraceID:    span.SpanContext().TraceID().String(),
+			SpanID:     span.SpanContext().SpanID().String(),
+			Name:       span.Name(),
+			StartTime:  span.StartTime(),
+			EndTime:    span.EndTime(),
+			StatusCode: span.Status().Code.String(),
+			Attributes: attributesToMap(span.Attributes()),
+		}
+		if span.Parent().IsValid() {
+			record.ParentSpanID = span.Parent().SpanID().String()
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal span record: %w", err)
+		}
+		if _, err := e.writer.Write(append(line, '\n')); err != nil {
+			return fmt.Errorf("failed to write span record: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *fileSpanExporter) Shutdown(context.Context) error { return nil }
+
+// ColumnarWriter persists one row per span to a columnar store such as
+// Cassandra or ClickHouse, keyed by trace_id/span_id. Implementations wrap
+// the store's own client; pulse_otel only defines the row shape and the
+// write contract.
+type ColumnarWriter interface {
+	// WriteSpanRow persists row in keyspace (Cassandra) or database
+	// (ClickHouse), applying ttl if non-zero.
+	WriteSpanRow(ctx context.Context, keyspace string, row SpanRow, ttl time.Duration) error
+}
+
+// SpanRow is one row of a columnar exporter's span table: enough to
+// reconstruct a span plus its events and links without the original
+// sdktrace.ReadOnlySpan.
+type SpanRow struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	Kind         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]string
+	StatusCode   string
+	StatusMsg    string
+	Events       []SpanRowEvent
+	Links        []SpanRowLink
+}
+
+// SpanRowEvent is one span event attached to a SpanRow.
+type SpanRowEvent struct {
+	Name       string
+	Timestamp  time.Time
+	Attributes map[string]string
+}
+
+// SpanRowLink is one span link attached to a SpanRow.
+type SpanRowLink struct {
+	TraceID    string
+	SpanID     string
+	Attributes map[string]string
+}
+
+type columnarSpanExporter struct {
+	writer   ColumnarWriter
+	keyspace string
+	ttl      time.Duration
+}
+
+func newColumnarExporter(_ context.Context, cfg ExporterConfig) (sdktrace.SpanExporter, error) {
+	if cfg.ColumnarWriter == nil {
+		return nil, fmt.Errorf("columnar exporter requires a ColumnarWriter")
+	}
+	return &columnarSpanExporter{writer: cfg.ColumnarWriter, keyspace: cfg.Keyspace, ttl: cfg.TTL}, nil
+}
+
+func (e *columnarSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		row := SpanRow{
+			TraceID:    span.SpanContext().TraceID().String(),
+			SpanID:     span.SpanContext().SpanID().String(),
+			Name:       span.Name(),
+			Kind:       span.SpanKind().String(),
+			StartTime:  span.StartTime(),
+			EndTime:    span.EndTime(),
+			Attributes: attributesToMap(span.Attributes()),
+			StatusCode: span.Status().Code.String(),
+			StatusMsg:  span.Status().Description,
+		}
+		if span.Parent().IsValid() {
+			row.ParentSpanID = span.Parent().SpanID().String()
+		}
+		for _, event := range span.Events() {
+			row.Events = append(row.Events, SpanRowEvent{
+				Name:       event.Name,
+				Timestamp:  event.Time,
+				Attributes: attributesToMap(event.Attributes),
+			})
+		}
+		for _, link := range span.Links() {
+			row.Links = append(row.Links, SpanRowLink{
+				TraceID:    link.SpanContext.TraceID().String(),
+				SpanID:     link.SpanContext.SpanID().String(),
+				Attributes: attributesToMap(link.Attributes),
+			})
+		}
+
+		if err := e.writer.WriteSpanRow(ctx, e.keyspace, row, e.ttl); err != nil {
+			return fmt.Errorf("failed to write span row: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *columnarSpanExporter) Shutdown(context.Context) error { return nil }
+
+func attributesToMap(attrs []attribute.KeyValue) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		m[string(attr.Key)] = attr.Value.Emit()
+	}
+	return m
+}
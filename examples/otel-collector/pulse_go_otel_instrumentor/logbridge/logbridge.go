@@ -0,0 +1,81 @@
+// Package logbridge wires pulse_otel's per-project tracers into popular Go
+// logging libraries (log/slog, zap, zerolog), so a log record emitted while
+// a request is active automatically gets trace_id, span_id, and project.id
+// fields, and -- with WithSpanEvents -- is also attached to the active span
+// as an event. This closes the loop between the tracing pulse_otel provides
+// and the logs applications still emit with log.Printf-style calls.
+package logbridge
+
+import (
+	"context"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures a bridge's correlation behavior via functional options.
+type Option func(*config)
+
+type config struct {
+	traceManager *pulse_otel.PulseTraceManager
+	spanEvents   bool
+}
+
+// WithSpanEvents additionally records every log record as an event on the
+// active span, with its severity and correlation fields as event
+// attributes, and marks the span failed for error/fatal records. Off by
+// default, since most applications already ship logs to a separate backend
+// and don't want them duplicated into trace storage.
+func WithSpanEvents() Option {
+	return func(c *config) { c.spanEvents = true }
+}
+
+func newConfig(traceManager *pulse_otel.PulseTraceManager, opts ...Option) *config {
+	c := &config{traceManager: traceManager}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// correlationAttrs returns the trace_id/span_id/project.id attributes for a
+// log record emitted under ctx, or nil if ctx carries no active span or its
+// project isn't one cfg.traceManager manages. This runs on every log call,
+// so it uses HasProject rather than GetTracerProvider: the latter creates a
+// provider (and dials an exporter) for any unrecognized project ID, which
+// would let routine logging spin up bogus providers and evict real ones
+// under Config.MaxProjects.
+func correlationAttrs(ctx context.Context, cfg *config) []attribute.KeyValue {
+	span := trace.SpanFromContext(ctx)
+	if !span.SpanContext().IsValid() {
+		return nil
+	}
+
+	projectID := pulse_otel.ProjectIDFromContext(ctx)
+	if !cfg.traceManager.HasProject(projectID) {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		attribute.String("trace_id", span.SpanContext().TraceID().String()),
+		attribute.String("span_id", span.SpanContext().SpanID().String()),
+		attribute.String("project.id", projectID),
+	}
+}
+
+// recordSpanEvent attaches a log record to the active span in ctx as an
+// event named after its message, so a trace view shows the logs emitted
+// during that span. severity "error"/"fatal" also marks the span failed.
+func recordSpanEvent(ctx context.Context, severity, message string, attrs []attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	eventAttrs := append([]attribute.KeyValue{attribute.String("log.severity", severity)}, attrs...)
+	span.AddEvent(message, trace.WithAttributes(eventAttrs...))
+	if severity == "error" || severity == "fatal" {
+		span.SetStatus(codes.Error, message)
+	}
+}
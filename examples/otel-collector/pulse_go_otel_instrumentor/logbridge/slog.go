@@ -0,0 +1,60 @@
+package logbridge
+
+import (
+	"context"
+	"log/slog"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+)
+
+// NewSlogHandler wraps base so every record logged through a context
+// carrying an active span gets trace_id, span_id, and project.id attributes
+// added automatically, and -- with WithSpanEvents -- is also recorded as an
+// event on that span. traceManager is typically obtained via
+// HTTPMiddleware.GetPulseTraceManager().
+func NewSlogHandler(traceManager *pulse_otel.PulseTraceManager, base slog.Handler, opts ...Option) slog.Handler {
+	return &slogHandler{base: base, cfg: newConfig(traceManager, opts...)}
+}
+
+type slogHandler struct {
+	base slog.Handler
+	cfg  *config
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := correlationAttrs(ctx, h.cfg)
+	for _, attr := range attrs {
+		record.AddAttrs(slog.String(string(attr.Key), attr.Value.AsString()))
+	}
+	if h.cfg.spanEvents {
+		recordSpanEvent(ctx, slogSeverity(record.Level), record.Message, attrs)
+	}
+	return h.base.Handle(ctx, record)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{base: h.base.WithAttrs(attrs), cfg: h.cfg}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{base: h.base.WithGroup(name), cfg: h.cfg}
+}
+
+// slogSeverity maps a slog.Level onto the severity labels pulse_otel's span
+// events use across every logbridge backend.
+func slogSeverity(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warn"
+	case level >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
@@ -0,0 +1,101 @@
+package logbridge
+
+import (
+	"context"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+// contextFieldKey is the zapcore.Field key Context embeds its
+// context.Context under. zapcore.Core.Write is never given the context a
+// log call was made with, so correlation only happens for entries that
+// include a field built with Context.
+const contextFieldKey = "pulse_otel.ctx"
+
+// Context returns a zap.Field that NewZapCore reads to correlate a log entry
+// with the active span and project ID, e.g.:
+//
+//	logger.Info("processed order", logbridge.Context(ctx), zap.String("order_id", id))
+func Context(ctx context.Context) zapcore.Field {
+	return zapcore.Field{Key: contextFieldKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// NewZapCore wraps base so any entry logged with a logbridge.Context(ctx)
+// field gets trace_id, span_id, and project.id fields added, and -- with
+// WithSpanEvents -- is also recorded as an event on the active span.
+// traceManager is typically obtained via HTTPMiddleware.GetPulseTraceManager().
+func NewZapCore(traceManager *pulse_otel.PulseTraceManager, base zapcore.Core, opts ...Option) zapcore.Core {
+	return &zapCore{Core: base, cfg: newConfig(traceManager, opts...)}
+}
+
+type zapCore struct {
+	zapcore.Core
+	cfg *config
+}
+
+func (c *zapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &zapCore{Core: c.Core.With(fields), cfg: c.cfg}
+}
+
+func (c *zapCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Core.Enabled(entry.Level) {
+		return ce.AddCore(entry, c)
+	}
+	return ce
+}
+
+func (c *zapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx, rest := extractContext(fields)
+	if ctx == nil {
+		return c.Core.Write(entry, fields)
+	}
+
+	attrs := correlationAttrs(ctx, c.cfg)
+	rest = append(rest, zapFieldsFromAttrs(attrs)...)
+	if c.cfg.spanEvents {
+		recordSpanEvent(ctx, zapSeverity(entry.Level), entry.Message, attrs)
+	}
+	return c.Core.Write(entry, rest)
+}
+
+// extractContext pulls the context.Context out of a logbridge.Context
+// field, if present, returning the remaining fields unchanged.
+func extractContext(fields []zapcore.Field) (context.Context, []zapcore.Field) {
+	rest := make([]zapcore.Field, 0, len(fields))
+	var ctx context.Context
+	for _, f := range fields {
+		if f.Key == contextFieldKey {
+			ctx, _ = f.Interface.(context.Context)
+			continue
+		}
+		rest = append(rest, f)
+	}
+	return ctx, rest
+}
+
+func zapFieldsFromAttrs(attrs []attribute.KeyValue) []zapcore.Field {
+	fields := make([]zapcore.Field, len(attrs))
+	for i, attr := range attrs {
+		fields[i] = zapcore.Field{Key: string(attr.Key), Type: zapcore.StringType, String: attr.Value.AsString()}
+	}
+	return fields
+}
+
+// zapSeverity maps a zapcore.Level onto the severity labels pulse_otel's
+// span events use across every logbridge backend.
+func zapSeverity(level zapcore.Level) string {
+	switch {
+	case level >= zapcore.FatalLevel:
+		return "fatal"
+	case level >= zapcore.ErrorLevel:
+		return "error"
+	case level >= zapcore.WarnLevel:
+		return "warn"
+	case level >= zapcore.InfoLevel:
+		return "info"
+	default:
+		return "debug"
+	}
+}
@@ -0,0 +1,45 @@
+package logbridge
+
+import (
+	"context"
+
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// NewZerologLogger returns base with trace_id, span_id, and project.id
+// fields bound in for the span and project active on ctx, plus -- with
+// WithSpanEvents -- a hook that mirrors every record logged through it onto
+// that span as an event. traceManager is typically obtained via
+// HTTPMiddleware.GetPulseTraceManager().
+//
+// Unlike NewSlogHandler/NewZapCore, correlation is bound once rather than
+// per call: zerolog.Hook.Run gets neither the record's fields nor a
+// context to pull them from, so there's nowhere to resolve the active span
+// per log call. Call this once per request -- alongside HTTPMiddleware.Handler
+// -- the same way a scoped zerolog.Logger is normally derived from ctx.
+func NewZerologLogger(traceManager *pulse_otel.PulseTraceManager, ctx context.Context, base zerolog.Logger, opts ...Option) zerolog.Logger {
+	cfg := newConfig(traceManager, opts...)
+
+	attrs := correlationAttrs(ctx, cfg)
+	logCtx := base.With()
+	for _, attr := range attrs {
+		logCtx = logCtx.Str(string(attr.Key), attr.Value.AsString())
+	}
+	logger := logCtx.Logger()
+
+	if cfg.spanEvents {
+		logger = logger.Hook(spanEventHook{ctx: ctx, attrs: attrs})
+	}
+	return logger
+}
+
+type spanEventHook struct {
+	ctx   context.Context
+	attrs []attribute.KeyValue
+}
+
+func (h spanEventHook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	recordSpanEvent(h.ctx, level.String(), message, h.attrs)
+}
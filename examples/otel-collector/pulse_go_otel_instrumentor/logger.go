@@ -0,0 +1,55 @@
+package pulse_otel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Logger lets HTTPMiddleware and InstrumentedTransport route their internal
+// diagnostics (project resolution, collector reachability, span creation)
+// through the host application's own logging stack instead of stdout.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// noopLogger discards everything. It's the default so embedding Pulse in a
+// production server never writes to stdout unless a Logger is configured.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
+func (noopLogger) Errorf(string, ...interface{}) {}
+
+// logger returns c.Logger, falling back to a no-op so callers never need a
+// nil check.
+func (c *Config) logger() Logger {
+	if c.Logger == nil {
+		return noopLogger{}
+	}
+	return c.Logger
+}
+
+// logDebug logs at debug level through logger and, when span is recording,
+// also records the message as a span event so it shows up alongside the
+// trace that was active when it happened.
+func logDebug(logger Logger, span trace.Span, format string, args ...interface{}) {
+	logger.Debugf(format, args...)
+	addSpanEvent(span, format, args...)
+}
+
+// logWarn is logDebug's warn-level counterpart.
+func logWarn(logger Logger, span trace.Span, format string, args ...interface{}) {
+	logger.Warnf(format, args...)
+	addSpanEvent(span, format, args...)
+}
+
+func addSpanEvent(span trace.Span, format string, args ...interface{}) {
+	if span != nil && span.IsRecording() {
+		span.AddEvent(fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,328 @@
+package pulse_otel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// ProjectMeterProvider holds the per-project meter provider along with the
+// HTTP semantic-convention instruments shared by HTTPMiddleware.
+type ProjectMeterProvider struct {
+	meterProvider *sdkmetric.MeterProvider
+
+	requestDuration  metric.Float64Histogram
+	activeRequests   metric.Int64UpDownCounter
+	requestBodySize  metric.Int64Histogram
+	responseBodySize metric.Int64Histogram
+}
+
+// PulseMetricsManager manages OpenTelemetry meter providers for multiple
+// projects, mirroring PulseTraceManager: the same MaxProjects/ProjectIdleTTL
+// eviction bounds projectID-keyed growth here too, since GetMeterProvider is
+// driven by the same attacker-controllable project ID as the trace path.
+type PulseMetricsManager struct {
+	projectMeterProviders map[string]*ProjectMeterProvider
+	lastAccess            map[string]time.Time
+	baseConfig            *Config
+	mutex                 sync.RWMutex
+	stopBackground        chan struct{}
+	backgroundDone        sync.WaitGroup
+}
+
+// NewPulseMetricsManager creates a new pulse metrics manager. A background
+// goroutine periodically evicts providers that have exceeded
+// ProjectIdleTTL, mirroring PulseTraceManager; stop it via Shutdown.
+func NewPulseMetricsManager(baseConfig *Config) *PulseMetricsManager {
+	if baseConfig == nil {
+		baseConfig = DefaultConfig()
+	}
+
+	mm := &PulseMetricsManager{
+		projectMeterProviders: make(map[string]*ProjectMeterProvider),
+		lastAccess:            make(map[string]time.Time),
+		baseConfig:            baseConfig,
+		stopBackground:        make(chan struct{}),
+	}
+
+	mm.backgroundDone.Add(1)
+	go mm.backgroundLoop()
+
+	return mm
+}
+
+// backgroundLoop periodically evicts meter providers that have been idle
+// for longer than ProjectIdleTTL, so the cost isn't paid on the request path.
+func (mm *PulseMetricsManager) backgroundLoop() {
+	defer mm.backgroundDone.Done()
+
+	interval := mm.baseConfig.ReachabilityCheckInterval
+	if interval <= 0 {
+		interval = defaultReachabilityCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-mm.stopBackground:
+			return
+		case <-ticker.C:
+			mm.evictIdleProjects()
+		}
+	}
+}
+
+// evictIdleProjects shuts down and removes providers that haven't been used
+// within ProjectIdleTTL. A zero TTL disables idle eviction.
+func (mm *PulseMetricsManager) evictIdleProjects() {
+	if mm.baseConfig.ProjectIdleTTL <= 0 {
+		return
+	}
+
+	now := time.Now()
+	mm.mutex.Lock()
+	var toEvict []string
+	for projectID, lastUsed := range mm.lastAccess {
+		if now.Sub(lastUsed) > mm.baseConfig.ProjectIdleTTL {
+			toEvict = append(toEvict, projectID)
+		}
+	}
+	mm.mutex.Unlock()
+
+	for _, projectID := range toEvict {
+		mm.evictProject(projectID)
+	}
+}
+
+// evictProject gracefully shuts down and removes a single project's
+// provider. Safe to call even if the project no longer exists.
+func (mm *PulseMetricsManager) evictProject(projectID string) {
+	mm.mutex.Lock()
+	provider, exists := mm.projectMeterProviders[projectID]
+	if !exists {
+		mm.mutex.Unlock()
+		return
+	}
+	delete(mm.projectMeterProviders, projectID)
+	delete(mm.lastAccess, projectID)
+	mm.mutex.Unlock()
+
+	if provider.meterProvider != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = provider.meterProvider.Shutdown(shutdownCtx)
+	}
+}
+
+// evictLeastRecentlyUsedLocked picks the least-recently-used project other
+// than keep once MaxProjects is exceeded, returning its ID and true; it does
+// not evict it itself, the caller does so via evictProject after releasing
+// mm.mutex. Callers must hold mm.mutex.
+func (mm *PulseMetricsManager) evictLeastRecentlyUsedLocked(keep string) (evict string, ok bool) {
+	if mm.baseConfig.MaxProjects <= 0 || len(mm.projectMeterProviders) <= mm.baseConfig.MaxProjects {
+		return "", false
+	}
+
+	var oldestProjectID string
+	var oldestAccess time.Time
+	for projectID := range mm.projectMeterProviders {
+		if projectID == keep {
+			continue
+		}
+		accessedAt := mm.lastAccess[projectID]
+		if oldestProjectID == "" || accessedAt.Before(oldestAccess) {
+			oldestProjectID = projectID
+			oldestAccess = accessedAt
+		}
+	}
+
+	if oldestProjectID == "" {
+		return "", false
+	}
+	return oldestProjectID, true
+}
+
+// HasProject reports whether projectID already has a meter provider, without
+// creating one, mirroring PulseTraceManager.HasProject.
+func (mm *PulseMetricsManager) HasProject(projectID string) bool {
+	mm.mutex.RLock()
+	defer mm.mutex.RUnlock()
+	_, exists := mm.projectMeterProviders[projectID]
+	return exists
+}
+
+// GetMeterProvider returns or creates a meter provider for a specific project
+func (mm *PulseMetricsManager) GetMeterProvider(projectID string) (*ProjectMeterProvider, error) {
+	mm.mutex.RLock()
+	provider, exists := mm.projectMeterProviders[projectID]
+	mm.mutex.RUnlock()
+
+	if exists {
+		mm.mutex.Lock()
+		mm.lastAccess[projectID] = time.Now()
+		mm.mutex.Unlock()
+		return provider, nil
+	}
+
+	mm.mutex.Lock()
+
+	// Double-check after acquiring write lock
+	if provider, exists := mm.projectMeterProviders[projectID]; exists {
+		mm.lastAccess[projectID] = time.Now()
+		mm.mutex.Unlock()
+		return provider, nil
+	}
+
+	provider, err := mm.createProjectMeterProvider(projectID)
+	if err != nil {
+		mm.mutex.Unlock()
+		return nil, fmt.Errorf("failed to create meter provider for project %s: %w", projectID, err)
+	}
+
+	mm.projectMeterProviders[projectID] = provider
+	mm.lastAccess[projectID] = time.Now()
+	evictID, shouldEvict := mm.evictLeastRecentlyUsedLocked(projectID)
+	mm.mutex.Unlock()
+
+	if shouldEvict {
+		mm.evictProject(evictID)
+	}
+
+	return provider, nil
+}
+
+func (mm *PulseMetricsManager) createProjectMeterProvider(projectID string) (*ProjectMeterProvider, error) {
+	ctx := context.Background()
+
+	res, err := createProjectResource(mm.baseConfig, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	// Form project-specific collector endpoint
+	collectorEndpointURL := "otel-collector:4318"
+
+	exporter, err := otlpmetrichttp.New(ctx,
+		otlpmetrichttp.WithEndpoint(collectorEndpointURL),
+		otlpmetrichttp.WithHeaders(mm.baseConfig.Headers),
+		otlpmetrichttp.WithTimeout(mm.baseConfig.Timeout),
+		otlpmetrichttp.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(res),
+	)
+
+	meter := provider.Meter("pulse-otel-http")
+
+	requestDuration, err := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP server requests"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http.server.request.duration: %w", err)
+	}
+
+	activeRequests, err := meter.Int64UpDownCounter(
+		"http.server.active_requests",
+		metric.WithDescription("Number of in-flight HTTP server requests"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http.server.active_requests: %w", err)
+	}
+
+	requestBodySize, err := meter.Int64Histogram(
+		"http.server.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server request bodies"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http.server.request.body.size: %w", err)
+	}
+
+	responseBodySize, err := meter.Int64Histogram(
+		"http.server.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server response bodies"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http.server.response.body.size: %w", err)
+	}
+
+	return &ProjectMeterProvider{
+		meterProvider:    provider,
+		requestDuration:  requestDuration,
+		activeRequests:   activeRequests,
+		requestBodySize:  requestBodySize,
+		responseBodySize: responseBodySize,
+	}, nil
+}
+
+// Shutdown stops the background eviction loop and gracefully shuts down all
+// project meter providers.
+func (mm *PulseMetricsManager) Shutdown(ctx context.Context) error {
+	close(mm.stopBackground)
+	mm.backgroundDone.Wait()
+
+	mm.mutex.Lock()
+	defer mm.mutex.Unlock()
+
+	var errors []error
+	for projectID, provider := range mm.projectMeterProviders {
+		if provider.meterProvider != nil {
+			if err := provider.meterProvider.Shutdown(ctx); err != nil {
+				errors = append(errors, fmt.Errorf("failed to shutdown meter provider for project %s: %w", projectID, err))
+			}
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("shutdown errors: %v", errors)
+	}
+
+	return nil
+}
+
+// clientHTTPInstrumentsT holds the client-side HTTP instruments.
+// getClientHTTPInstruments resolves them from the meter it is given on every
+// call, so when that meter comes from the global meter provider (which
+// HTTPMiddleware.Handler points at the active project) client metrics follow
+// the provider swap, mirroring how InstrumentedTransport resolves its tracer
+// from the global tracer provider on every request.
+type clientHTTPInstrumentsT struct {
+	requestDuration  metric.Float64Histogram
+	requestBodySize  metric.Int64Histogram
+	responseBodySize metric.Int64Histogram
+}
+
+func getClientHTTPInstruments(meter metric.Meter) clientHTTPInstrumentsT {
+	var instruments clientHTTPInstrumentsT
+	instruments.requestDuration, _ = meter.Float64Histogram(
+		"http.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of outbound HTTP client requests"),
+	)
+	instruments.requestBodySize, _ = meter.Int64Histogram(
+		"http.client.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of outbound HTTP client request bodies"),
+	)
+	instruments.responseBodySize, _ = meter.Int64Histogram(
+		"http.client.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of outbound HTTP client response bodies"),
+	)
+	return instruments
+}
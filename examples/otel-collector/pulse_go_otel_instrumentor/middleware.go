@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
@@ -19,25 +21,105 @@ import (
 
 // HTTPMiddleware provides HTTP instrumentation middleware
 type HTTPMiddleware struct {
-	pulseTraceManager *PulseTraceManager
-	serviceName       string
+	pulseTraceManager   *PulseTraceManager
+	pulseMetricsManager *PulseMetricsManager
+	serviceName         string
+	publicEndpointFn    func(*http.Request) bool
+	spanNameFormatter   func(*http.Request) string
+	routeResolver       func(*http.Request) string
+}
+
+// HTTPMiddlewareOption configures an HTTPMiddleware via functional options.
+type HTTPMiddlewareOption func(*HTTPMiddleware)
+
+// WithPublicEndpoint marks every request handled by this middleware as
+// arriving from an untrusted public edge: instead of making the server span
+// a child of whatever trace context the caller sent, a new root span is
+// started and the extracted remote context is attached as a span link.
+func WithPublicEndpoint() HTTPMiddlewareOption {
+	return func(m *HTTPMiddleware) {
+		m.publicEndpointFn = func(*http.Request) bool { return true }
+	}
+}
+
+// WithPublicEndpointFn is like WithPublicEndpoint but decides per-request,
+// so a single middleware instance can serve both public and internal routes.
+func WithPublicEndpointFn(fn func(*http.Request) bool) HTTPMiddlewareOption {
+	return func(m *HTTPMiddleware) {
+		m.publicEndpointFn = fn
+	}
+}
+
+// WithSpanNameFormatter lets callers control the server span name, which by
+// default is "<method> <route>" and can otherwise explode cardinality when
+// the resolved route still contains path parameters.
+func WithSpanNameFormatter(fn func(*http.Request) string) HTTPMiddlewareOption {
+	return func(m *HTTPMiddleware) {
+		m.spanNameFormatter = fn
+	}
+}
+
+// WithRouteResolver supplies the matched route template (e.g.
+// "/users/{id}") for frameworks like chi/gorilla/gin that know it, so
+// http.route and the default span name don't end up keyed by raw path.
+func WithRouteResolver(fn func(*http.Request) string) HTTPMiddlewareOption {
+	return func(m *HTTPMiddleware) {
+		m.routeResolver = fn
+	}
+}
+
+// route resolves the route template to attribute to a request, falling
+// back to the raw URL path when no RouteResolver is configured or it
+// returns an empty string.
+func (m *HTTPMiddleware) route(r *http.Request) string {
+	if m.routeResolver != nil {
+		if route := m.routeResolver(r); route != "" {
+			return route
+		}
+	}
+	return r.URL.Path
+}
+
+// spanName resolves the server span name for a request, preferring a
+// configured SpanNameFormatter over the "<method> <route>" default.
+func (m *HTTPMiddleware) spanName(r *http.Request, route string) string {
+	if m.spanNameFormatter != nil {
+		return m.spanNameFormatter(r)
+	}
+	return fmt.Sprintf("%s %s", r.Method, route)
 }
 
 // NewHTTPMiddleware creates a new HTTP middleware with project support
-func NewHTTPMiddleware(serviceName string, baseConfig *Config) *HTTPMiddleware {
+func NewHTTPMiddleware(serviceName string, baseConfig *Config, opts ...HTTPMiddlewareOption) *HTTPMiddleware {
 	// Set up global OpenTelemetry providers so instrumented libraries can use them
 	setupGlobalOTelProviders(baseConfig)
 
-	return &HTTPMiddleware{
-		pulseTraceManager: NewPulseTraceManager(baseConfig),
-		serviceName:       serviceName,
+	m := &HTTPMiddleware{
+		pulseTraceManager:   NewPulseTraceManager(baseConfig),
+		pulseMetricsManager: NewPulseMetricsManager(baseConfig),
+		serviceName:         serviceName,
 	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// isPublicEndpoint reports whether r should be treated as arriving from an
+// untrusted public edge.
+func (m *HTTPMiddleware) isPublicEndpoint(r *http.Request) bool {
+	return m.publicEndpointFn != nil && m.publicEndpointFn(r)
 }
 
 // setupGlobalOTelProviders configures global OpenTelemetry providers
 func setupGlobalOTelProviders(baseConfig *Config) {
-	// Create a default tracer provider for global use
-	defaultProvider, err := NewPulseTraceManager(baseConfig).GetTracerProvider("default")
+	// Build the default tracer provider directly rather than through a
+	// PulseTraceManager: a manager's background eviction/reachability-sweep
+	// goroutine only pays for itself when something can later call its
+	// Shutdown, and nothing retains this one-off manager to do so.
+	defaultProvider, err := newProjectTraceProvider(baseConfig, "default")
 	if err == nil {
 		// Set the global tracer provider so instrumented libraries can use it
 		otel.SetTracerProvider(defaultProvider.traceProvider)
@@ -55,9 +137,27 @@ func (m *HTTPMiddleware) GetPulseTraceManager() *PulseTraceManager {
 	return m.pulseTraceManager
 }
 
-// Shutdown gracefully shuts down the middleware and its project manager
+// GetPulseMetricsManager returns the pulse metrics manager instance
+func (m *HTTPMiddleware) GetPulseMetricsManager() *PulseMetricsManager {
+	return m.pulseMetricsManager
+}
+
+// EndpointStatus returns the current collector health state for every
+// project this middleware has handled a request for, keyed by project ID.
+// Wire it into a /health handler to surface collector outages without
+// polling each project's provider directly.
+func (m *HTTPMiddleware) EndpointStatus() map[string]HealthState {
+	return m.pulseTraceManager.EndpointStatus()
+}
+
+// Shutdown gracefully shuts down the middleware and its project managers
 func (m *HTTPMiddleware) Shutdown(ctx context.Context) error {
-	return m.pulseTraceManager.Shutdown(ctx)
+	traceErr := m.pulseTraceManager.Shutdown(ctx)
+	metricsErr := m.pulseMetricsManager.Shutdown(ctx)
+	if traceErr != nil {
+		return traceErr
+	}
+	return metricsErr
 }
 
 // Handler wraps an http.Handler with opentelemetry instrumentation
@@ -71,13 +171,14 @@ func (m *HTTPMiddleware) Handler(handler http.Handler) http.Handler {
 			projectID = m.extractProjectIDFromBody(r)
 		}
 
-		fmt.Println("Project ID extracted:", projectID)
+		logger := m.pulseTraceManager.baseConfig.logger()
+		logger.Debugf("Project ID extracted: %s", projectID)
 
 		// Get project-specific tracer provider
 		provider, err := m.pulseTraceManager.GetTracerProvider(projectID)
 		if err != nil {
 			// Log error and use default behavior
-			fmt.Printf("Error getting project provider for %s: %v\n", projectID, err)
+			logger.Errorf("Error getting project provider for %s: %v", projectID, err)
 			handler.ServeHTTP(w, r)
 			return
 		}
@@ -85,12 +186,12 @@ func (m *HTTPMiddleware) Handler(handler http.Handler) http.Handler {
 		// Check and update collector reachability safely
 		isCollectorReachable, err := m.pulseTraceManager.CheckAndUpdateCollectorReachability(projectID)
 		if err != nil {
-			fmt.Printf("Error checking collector reachability for project %s: %v\n", projectID, err)
+			logger.Errorf("Error checking collector reachability for project %s: %v", projectID, err)
 			handler.ServeHTTP(w, r)
 			return
 		}
 
-		fmt.Println("isCollectorReachable:", isCollectorReachable)
+		logger.Debugf("isCollectorReachable: %t", isCollectorReachable)
 		if !isCollectorReachable {
 			// If collector is not reachable, skip tracing
 			handler.ServeHTTP(w, r)
@@ -99,23 +200,56 @@ func (m *HTTPMiddleware) Handler(handler http.Handler) http.Handler {
 
 		tracer := provider.traceProvider.Tracer(m.serviceName)
 
+		// Stash the project ID on the context so code that only has a
+		// context.Context to work with (e.g. pulse_otel/dbinstr) can resolve
+		// the same per-project tracer this request is using.
+		ctx := ContextWithProjectID(r.Context(), projectID)
+
 		// Extract any existing trace context from incoming request headers
-		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
 
-		spanName := fmt.Sprintf("%s %s", r.Method, r.URL.Path)
-		ctx, span := tracer.Start(ctx, spanName,
+		route := m.route(r)
+		spanStartOpts := []trace.SpanStartOption{
 			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(
-				semconv.HTTPRoute(r.URL.Path),
+				semconv.HTTPRoute(route),
 				attribute.String("project.id", projectID),
 			),
-		)
+		}
+
+		// For public endpoints we don't trust the caller's trace context
+		// enough to parent our span on it: start a new root span and keep
+		// the extracted remote context around only as a link.
+		if m.isPublicEndpoint(r) {
+			if remoteSpanCtx := trace.SpanContextFromContext(ctx); remoteSpanCtx.IsValid() {
+				spanStartOpts = append(spanStartOpts, trace.WithLinks(trace.Link{SpanContext: remoteSpanCtx}))
+			}
+			spanStartOpts = append(spanStartOpts, trace.WithNewRoot())
+		}
+
+		ctx, span := tracer.Start(ctx, m.spanName(r, route), spanStartOpts...)
 		defer span.End()
+		logDebug(logger, span, "Created server span for %s %s", r.Method, route)
 
 		// IMPORTANT: Set the global tracer provider to the project-specific one
 		// This ensures that any instrumented library will use the correct tracer provider
 		otel.SetTracerProvider(provider.traceProvider)
 
+		metricAttrs := metric.WithAttributes(attribute.String("project.id", projectID))
+		meterProvider, metricsErr := m.pulseMetricsManager.GetMeterProvider(projectID)
+		if metricsErr == nil {
+			// Mirrors the tracer provider swap above: client-side metrics
+			// recorded via the global meter provider land on this project.
+			otel.SetMeterProvider(meterProvider.meterProvider)
+			meterProvider.activeRequests.Add(ctx, 1, metricAttrs)
+			defer meterProvider.activeRequests.Add(ctx, -1, metricAttrs)
+		}
+
+		cfg := m.pulseTraceManager.baseConfig
+		captureHeaders(span, "request", r.Header, cfg.CapturedRequestHeaders, cfg.HeaderRedactor)
+		captureQueryParams(span, r.URL.Query(), cfg.CapturedQueryParams)
+		captureRequestBody(span, r, cfg.CaptureRequestBodyMaxBytes)
+
 		wrappedWriter := &responseWriter{
 			ResponseWriter: w,
 			statusCode:     http.StatusOK,
@@ -132,6 +266,19 @@ func (m *HTTPMiddleware) Handler(handler http.Handler) http.Handler {
 		span.SetAttributes(
 			attribute.Float64("http.duration_ms", float64(duration.Nanoseconds())/1000000),
 		)
+		captureHeaders(span, "response", wrappedWriter.Header(), cfg.CapturedResponseHeaders, cfg.HeaderRedactor)
+
+		if metricsErr == nil {
+			statusAttrs := metric.WithAttributes(
+				attribute.String("project.id", projectID),
+				attribute.Int("http.status_code", wrappedWriter.statusCode),
+			)
+			meterProvider.requestDuration.Record(ctx, duration.Seconds(), statusAttrs)
+			meterProvider.responseBodySize.Record(ctx, wrappedWriter.bytesWritten, statusAttrs)
+			if r.ContentLength >= 0 {
+				meterProvider.requestBodySize.Record(ctx, r.ContentLength, statusAttrs)
+			}
+		}
 
 		// Set span status based on HTTP status code
 		if wrappedWriter.statusCode >= 400 {
@@ -202,43 +349,170 @@ func (rw *responseWriter) Write(data []byte) (int, error) {
 	return n, err
 }
 
+// TransportOption configures an InstrumentedTransport via functional options.
+type TransportOption func(*InstrumentedTransport)
+
+// WithTransportConfig enables request/response header capture (with
+// HeaderRedactor) and cfg's Logger on the transport, as HTTPMiddleware does
+// for inbound requests. CapturedQueryParams and CaptureRequestBodyMaxBytes
+// are not applied to outbound requests.
+func WithTransportConfig(cfg *Config) TransportOption {
+	return func(t *InstrumentedTransport) {
+		t.config = cfg
+	}
+}
+
+// WithClientSpanNameFormatter overrides the default "HTTP <method> <host>"
+// client span name.
+func WithClientSpanNameFormatter(fn func(*http.Request) string) TransportOption {
+	return func(t *InstrumentedTransport) {
+		t.spanNameFormatter = fn
+	}
+}
+
+// WithTraceManager resolves client spans via the per-project tracer
+// traceManager builds for the project ID active on the outgoing request's
+// context (populated by HTTPMiddleware.Handler via ContextWithProjectID), so
+// a client span lands on the same provider as the server span that
+// triggered it. Without this option the transport falls back to the
+// process-global tracer provider, as it always has.
+func WithTraceManager(tm *PulseTraceManager) TransportOption {
+	return func(t *InstrumentedTransport) {
+		t.traceManager = tm
+	}
+}
+
+// WithHostFilter restricts instrumentation to requests whose host passes
+// allowed; other requests are proxied straight to the base transport with
+// no span and no header injection. Defaults to instrumenting every host.
+func WithHostFilter(allowed func(host string) bool) TransportOption {
+	return func(t *InstrumentedTransport) {
+		t.hostFilter = allowed
+	}
+}
+
+// WithAllowedHosts restricts instrumentation to exactly these hosts
+// (case-insensitive).
+func WithAllowedHosts(hosts ...string) TransportOption {
+	allow := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		allow[strings.ToLower(host)] = struct{}{}
+	}
+	return WithHostFilter(func(host string) bool {
+		_, ok := allow[strings.ToLower(host)]
+		return ok
+	})
+}
+
+// WithDeniedHosts excludes these hosts (case-insensitive) from
+// instrumentation while instrumenting everything else. Use it to keep
+// internal/loopback traffic out of traces when InstallDefaultTransport
+// instruments every outgoing request.
+func WithDeniedHosts(hosts ...string) TransportOption {
+	deny := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		deny[strings.ToLower(host)] = struct{}{}
+	}
+	return WithHostFilter(func(host string) bool {
+		_, denied := deny[strings.ToLower(host)]
+		return !denied
+	})
+}
+
 // GetInstrumentedHTTPClient returns an HTTP client that will automatically
 // create spans for outgoing requests when used within a traced context
-func GetInstrumentedHTTPClient() *http.Client {
-	return &http.Client{
-		Transport: &InstrumentedTransport{
-			base: http.DefaultTransport,
-		},
+func GetInstrumentedHTTPClient(opts ...TransportOption) *http.Client {
+	return &http.Client{Transport: WrapTransport(http.DefaultTransport, opts...)}
+}
+
+// WrapTransport wraps base with the same client-span and W3C-propagation
+// instrumentation GetInstrumentedHTTPClient gives its *http.Client, so
+// unmodified http.Get/http.Post calls and third-party SDKs built on a plain
+// http.RoundTripper get client spans without callers constructing an
+// instrumented *http.Client themselves. base defaults to http.DefaultTransport
+// when nil.
+func WrapTransport(base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &InstrumentedTransport{base: base}
+	for _, opt := range opts {
+		opt(t)
 	}
+	return t
+}
+
+// InstallDefaultTransport swaps http.DefaultTransport for one wrapped with
+// WrapTransport, so any code using the zero-value http.Client (including
+// http.Get/http.Post and most third-party SDKs that don't set their own
+// Transport) picks up client spans and trace propagation automatically. Call
+// it once at startup.
+func InstallDefaultTransport(opts ...TransportOption) {
+	http.DefaultTransport = WrapTransport(http.DefaultTransport, opts...)
 }
 
 // InstrumentedTransport wraps http.RoundTripper to add automatic tracing
 type InstrumentedTransport struct {
-	base http.RoundTripper
+	base              http.RoundTripper
+	config            *Config
+	spanNameFormatter func(*http.Request) string
+	traceManager      *PulseTraceManager
+	hostFilter        func(host string) bool
+}
+
+// spanName resolves the client span name for req, preferring a formatter
+// set via WithClientSpanNameFormatter over the "HTTP <method> <host>" default.
+func (t *InstrumentedTransport) spanName(req *http.Request) string {
+	if t.spanNameFormatter != nil {
+		return t.spanNameFormatter(req)
+	}
+	return fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Host)
+}
+
+// tracer resolves the client tracer for ctx: the per-project tracer for the
+// active project ID when traceManager is configured, falling back to the
+// process-global tracer provider otherwise.
+func (t *InstrumentedTransport) tracer(ctx context.Context) trace.Tracer {
+	if t.traceManager != nil {
+		if provider, err := t.traceManager.GetTracerProvider(ProjectIDFromContext(ctx)); err == nil {
+			return provider.TraceProvider().Tracer("http-client")
+		}
+	}
+	return otel.Tracer("http-client")
 }
 
 // RoundTrip implements http.RoundTripper and automatically creates spans for HTTP requests
 func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	ctx := req.Context()
 
-	// Debug: Print context information
-	fmt.Printf("HTTP Client: Request URL: %s\n", req.URL.String())
+	var logger Logger = noopLogger{}
+	if t.config != nil {
+		logger = t.config.logger()
+	}
+
+	logger.Debugf("HTTP Client: Request URL: %s", req.URL.String())
+
+	if t.hostFilter != nil && !t.hostFilter(req.URL.Hostname()) {
+		logger.Debugf("HTTP Client: Host %s excluded by host filter, passing through", req.URL.Hostname())
+		return t.base.RoundTrip(req)
+	}
 
 	// Get the active span from context (if any)
 	span := trace.SpanFromContext(ctx)
-	fmt.Printf("HTTP Client: Active span found: %t, Recording: %t\n", span != nil, span.IsRecording())
+	logger.Debugf("HTTP Client: Active span found: %t, Recording: %t", span != nil, span.IsRecording())
 
 	if !span.IsRecording() {
 		// No active span, just pass through
-		fmt.Println("HTTP Client: No recording span, passing through")
+		logger.Debugf("HTTP Client: No recording span, passing through")
 		return t.base.RoundTrip(req)
 	}
 
-	// Get tracer from the global tracer provider
-	tracer := otel.Tracer("http-client")
+	// Resolve the tracer for the project active on ctx, falling back to the
+	// process-global tracer provider when no PulseTraceManager was configured.
+	tracer := t.tracer(ctx)
 
 	// Create a new span for the HTTP request
-	spanName := fmt.Sprintf("HTTP %s %s", req.Method, req.URL.Host)
+	spanName := t.spanName(req)
 	ctx, clientSpan := tracer.Start(ctx, spanName,
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(
@@ -251,7 +525,11 @@ func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 	)
 	defer clientSpan.End()
 
-	fmt.Printf("HTTP Client: Created client span: %s\n", spanName)
+	logDebug(logger, clientSpan, "HTTP Client: Created client span: %s", spanName)
+
+	if t.config != nil {
+		captureHeaders(clientSpan, "request", req.Header, t.config.CapturedRequestHeaders, t.config.HeaderRedactor)
+	}
 
 	// Inject trace context into request headers for downstream propagation
 	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
@@ -259,6 +537,8 @@ func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 	// Update request with new context
 	req = req.WithContext(ctx)
 
+	instruments := getClientHTTPInstruments(otel.Meter("http-client"))
+
 	// Make the request
 	start := time.Now()
 	resp, err := t.base.RoundTrip(req)
@@ -272,14 +552,35 @@ func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, er
 	if err != nil {
 		clientSpan.RecordError(err)
 		clientSpan.SetStatus(codes.Error, err.Error())
+		instruments.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.host", req.URL.Host),
+		))
 		return resp, err
 	}
 
+	if t.config != nil {
+		captureHeaders(clientSpan, "response", resp.Header, t.config.CapturedResponseHeaders, t.config.HeaderRedactor)
+	}
+
 	// Add response status
 	clientSpan.SetAttributes(
 		attribute.Int("http.status_code", resp.StatusCode),
 	)
 
+	metricAttrs := metric.WithAttributes(
+		attribute.String("http.method", req.Method),
+		attribute.String("http.host", req.URL.Host),
+		attribute.Int("http.status_code", resp.StatusCode),
+	)
+	instruments.requestDuration.Record(ctx, duration.Seconds(), metricAttrs)
+	if req.ContentLength >= 0 {
+		instruments.requestBodySize.Record(ctx, req.ContentLength, metricAttrs)
+	}
+	if resp.ContentLength >= 0 {
+		instruments.responseBodySize.Record(ctx, resp.ContentLength, metricAttrs)
+	}
+
 	// Set span status based on HTTP status code
 	if resp.StatusCode >= 400 {
 		clientSpan.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
@@ -0,0 +1,185 @@
+// Package msginstr extends pulse_otel's instrumentation beyond HTTP to
+// message-driven workers: a Watermill HandlerMiddleware that creates a
+// consumer span per handled message (mirroring pulse_otel.HTTPMiddleware for
+// HTTP servers) and a PublisherDecorator that injects W3C trace context into
+// published messages so the next consumer can continue the trace.
+package msginstr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	pulse_otel "github.com/aanshu-ss/s2-otel-instrumentation-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultProjectMetadataKey is the message metadata key ConsumerMiddleware
+// reads the project ID from, analogous to the "singlestore-project-id" HTTP
+// header pulse_otel.HTTPMiddleware reads on the HTTP side.
+const DefaultProjectMetadataKey = "x-project-id"
+
+// ConsumerMiddleware provides Watermill router instrumentation analogous to
+// pulse_otel.HTTPMiddleware: a HandlerMiddleware that creates a consumer span
+// per message, and a PublisherDecorator that injects trace context into
+// published messages.
+type ConsumerMiddleware struct {
+	traceManager       *pulse_otel.PulseTraceManager
+	serviceName        string
+	projectMetadataKey string
+	spanNameFormatter  func(*message.Message) string
+}
+
+// ConsumerMiddlewareOption configures a ConsumerMiddleware via functional
+// options.
+type ConsumerMiddlewareOption func(*ConsumerMiddleware)
+
+// WithProjectMetadataKey overrides the default "x-project-id" metadata key.
+func WithProjectMetadataKey(key string) ConsumerMiddlewareOption {
+	return func(m *ConsumerMiddleware) { m.projectMetadataKey = key }
+}
+
+// WithSpanNameFormatter lets callers control the consumer span name, which
+// by default is "<topic> receive" using the "topic" metadata key most
+// Watermill subscribers set, falling back to "message receive" when absent.
+func WithSpanNameFormatter(fn func(*message.Message) string) ConsumerMiddlewareOption {
+	return func(m *ConsumerMiddleware) { m.spanNameFormatter = fn }
+}
+
+// NewConsumerMiddleware creates a ConsumerMiddleware backed by its own
+// PulseTraceManager, mirroring pulse_otel.NewHTTPMiddleware. Call Shutdown
+// when the consumer app stops to release its exporters.
+func NewConsumerMiddleware(serviceName string, baseConfig *pulse_otel.Config, opts ...ConsumerMiddlewareOption) *ConsumerMiddleware {
+	m := &ConsumerMiddleware{
+		traceManager:       pulse_otel.NewPulseTraceManager(baseConfig),
+		serviceName:        serviceName,
+		projectMetadataKey: DefaultProjectMetadataKey,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// GetPulseTraceManager returns the trace manager backing this middleware, so
+// it can be shared with, e.g., a dbinstr hook handling work triggered by a
+// consumed message.
+func (m *ConsumerMiddleware) GetPulseTraceManager() *pulse_otel.PulseTraceManager {
+	return m.traceManager
+}
+
+// Shutdown releases the middleware's per-project trace providers. Integrate
+// it with the same lifecycle as pulse_otel.HTTPMiddleware.Shutdown, e.g.
+// calling it alongside the Watermill router's own Close.
+func (m *ConsumerMiddleware) Shutdown(ctx context.Context) error {
+	return m.traceManager.Shutdown(ctx)
+}
+
+func (m *ConsumerMiddleware) spanName(msg *message.Message) string {
+	if m.spanNameFormatter != nil {
+		return m.spanNameFormatter(msg)
+	}
+	if topic := msg.Metadata.Get("topic"); topic != "" {
+		return fmt.Sprintf("%s receive", topic)
+	}
+	return "message receive"
+}
+
+// Middleware wraps a message.HandlerFunc with OpenTelemetry instrumentation,
+// the Watermill analogue of HTTPMiddleware.Handler: it extracts any W3C
+// trace context carried in the message's metadata, starts a consumer span on
+// the per-project tracer resolved from projectMetadataKey, and stashes the
+// project ID on the message's context so downstream code (e.g. dbinstr
+// hooks) resolves the same tracer.
+func (m *ConsumerMiddleware) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		projectID := msg.Metadata.Get(m.projectMetadataKey)
+
+		// Messages with no project metadata use the global tracer provider
+		// rather than a provider for the "" project. Any other project ID
+		// gets its provider from m.traceManager, which creates one the first
+		// time the ID is seen, as HTTPMiddleware does; the global provider
+		// is also used if that fails, the same fallback client/grpc.go and
+		// client/openapi.go use.
+		tracerProvider := otel.GetTracerProvider()
+		if projectID != "" {
+			if provider, err := m.traceManager.GetTracerProvider(projectID); err == nil {
+				tracerProvider = provider.TraceProvider()
+			}
+		}
+
+		ctx := pulse_otel.ContextWithProjectID(msg.Context(), projectID)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(msg.Metadata))
+
+		tracer := tracerProvider.Tracer(m.serviceName)
+		ctx, span := tracer.Start(ctx, m.spanName(msg),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("project.id", projectID),
+				attribute.String("messaging.message_id", msg.UUID),
+			),
+		)
+		defer span.End()
+
+		msg.SetContext(ctx)
+
+		produced, err := h(msg)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return produced, err
+		}
+
+		span.SetStatus(codes.Ok, "")
+		return produced, nil
+	}
+}
+
+// PublisherDecorator returns a message.PublisherDecorator that injects W3C
+// trace context into every published message's metadata, so the next
+// consumer's ConsumerMiddleware continues the same trace. Pass the result to
+// message.NewPublisherDecorator (or a Watermill router's AddPublisherDecorators).
+func (m *ConsumerMiddleware) PublisherDecorator() message.PublisherDecorator {
+	return func(pub message.Publisher) (message.Publisher, error) {
+		return &instrumentedPublisher{next: pub}, nil
+	}
+}
+
+type instrumentedPublisher struct {
+	next message.Publisher
+}
+
+func (p *instrumentedPublisher) Publish(topic string, messages ...*message.Message) error {
+	for _, msg := range messages {
+		if msg.Metadata == nil {
+			msg.Metadata = message.Metadata{}
+		}
+		otel.GetTextMapPropagator().Inject(msg.Context(), metadataCarrier(msg.Metadata))
+	}
+	return p.next.Publish(topic, messages...)
+}
+
+func (p *instrumentedPublisher) Close() error {
+	return p.next.Close()
+}
+
+// metadataCarrier adapts message.Metadata to propagation.TextMapCarrier so
+// W3C trace context can be injected into / extracted from it.
+type metadataCarrier message.Metadata
+
+func (c metadataCarrier) Get(key string) string { return message.Metadata(c).Get(key) }
+
+func (c metadataCarrier) Set(key, value string) { message.Metadata(c).Set(key, value) }
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,26 @@
+package pulse_otel
+
+import "context"
+
+type contextKey string
+
+// projectIDContextKey is the context key HTTPMiddleware stashes the resolved
+// project ID under, so code running further down the call stack (database
+// drivers, message consumers, anything that only has a context.Context to
+// work with) can resolve the same per-project tracer the request is using.
+const projectIDContextKey contextKey = "pulse-otel-project-id"
+
+// ContextWithProjectID returns a copy of ctx carrying projectID. HTTPMiddleware
+// calls this for every request; callers instrumenting code that doesn't go
+// through HTTPMiddleware (background jobs, message consumers) can call it
+// directly to get the same per-project routing.
+func ContextWithProjectID(ctx context.Context, projectID string) context.Context {
+	return context.WithValue(ctx, projectIDContextKey, projectID)
+}
+
+// ProjectIDFromContext returns the project ID stashed by ContextWithProjectID,
+// or "" if ctx doesn't carry one.
+func ProjectIDFromContext(ctx context.Context) string {
+	projectID, _ := ctx.Value(projectIDContextKey).(string)
+	return projectID
+}
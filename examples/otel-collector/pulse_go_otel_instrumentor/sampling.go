@@ -0,0 +1,135 @@
+package pulse_otel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	apitrace "go.opentelemetry.io/otel/trace"
+)
+
+// SamplerType selects the sampling strategy used for a project.
+type SamplerType string
+
+const (
+	SamplerAlways                  SamplerType = "always"
+	SamplerNever                   SamplerType = "never"
+	SamplerTraceIDRatio            SamplerType = "traceidratio"
+	SamplerParentBasedAlways       SamplerType = "parentbased_always"
+	SamplerParentBasedNever        SamplerType = "parentbased_never"
+	SamplerParentBasedTraceIDRatio SamplerType = "parentbased_traceidratio"
+	SamplerRateLimited             SamplerType = "ratelimited"
+)
+
+// SamplingConfig controls how a project's spans are sampled before export.
+type SamplingConfig struct {
+	Type SamplerType
+	// Ratio is used by SamplerTraceIDRatio and SamplerParentBasedTraceIDRatio.
+	Ratio float64
+	// RateLimit is the target number of spans per second allowed through,
+	// used by SamplerRateLimited.
+	RateLimit float64
+}
+
+// DefaultSamplingConfig returns the always-on sampler, matching prior behavior.
+func DefaultSamplingConfig() SamplingConfig {
+	return SamplingConfig{Type: SamplerAlways}
+}
+
+// resolveSamplingConfig returns the sampling config for a project, falling
+// back to the base config's default when no per-project override exists.
+func (c *Config) resolveSamplingConfig(projectID string) SamplingConfig {
+	if c.ProjectSampling != nil {
+		if cfg, ok := c.ProjectSampling[projectID]; ok {
+			return cfg
+		}
+	}
+	if c.DefaultSampling.Type == "" {
+		return DefaultSamplingConfig()
+	}
+	return c.DefaultSampling
+}
+
+// buildSampler constructs an OpenTelemetry sampler for the given config.
+func buildSampler(cfg SamplingConfig) (trace.Sampler, error) {
+	switch cfg.Type {
+	case "", SamplerAlways:
+		return trace.AlwaysSample(), nil
+	case SamplerNever:
+		return trace.NeverSample(), nil
+	case SamplerTraceIDRatio:
+		return trace.TraceIDRatioBased(cfg.Ratio), nil
+	case SamplerParentBasedAlways:
+		return trace.ParentBased(trace.AlwaysSample()), nil
+	case SamplerParentBasedNever:
+		return trace.ParentBased(trace.NeverSample()), nil
+	case SamplerParentBasedTraceIDRatio:
+		return trace.ParentBased(trace.TraceIDRatioBased(cfg.Ratio)), nil
+	case SamplerRateLimited:
+		return newRateLimitedSampler(cfg.RateLimit), nil
+	default:
+		return nil, fmt.Errorf("unknown sampler type %q", cfg.Type)
+	}
+}
+
+// rateLimitedSampler is a token-bucket sampler that admits at most
+// RateLimit spans per second, refilling continuously between decisions.
+type rateLimitedSampler struct {
+	mutex      sync.Mutex
+	ratePerSec float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRateLimitedSampler(ratePerSec float64) *rateLimitedSampler {
+	if ratePerSec <= 0 {
+		ratePerSec = 1
+	}
+	return &rateLimitedSampler{
+		ratePerSec: ratePerSec,
+		tokens:     ratePerSec,
+		lastRefill: time.Now(),
+	}
+}
+
+func (s *rateLimitedSampler) ShouldSample(params trace.SamplingParameters) trace.SamplingResult {
+	psc := trace.SamplingResult{Tracestate: apitrace.SpanContextFromContext(params.ParentContext).TraceState()}
+
+	if s.allow() {
+		psc.Decision = trace.RecordAndSample
+	} else {
+		psc.Decision = trace.Drop
+	}
+	return psc
+}
+
+func (s *rateLimitedSampler) allow() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(s.lastRefill).Seconds()
+	s.lastRefill = now
+
+	// The bucket holds at least one token so rates below 1/s still admit
+	// a span once enough time has passed.
+	capacity := s.ratePerSec
+	if capacity < 1 {
+		capacity = 1
+	}
+	s.tokens += elapsed * s.ratePerSec
+	if s.tokens > capacity {
+		s.tokens = capacity
+	}
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitedSampler) Description() string {
+	return fmt.Sprintf("RateLimitedSampler{%.2f/s}", s.ratePerSec)
+}
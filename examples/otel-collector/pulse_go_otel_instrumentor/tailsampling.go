@@ -0,0 +1,249 @@
+package pulse_otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultMaxBufferAge bounds how long a trace can sit buffered with no
+	// root span seen (a panicking handler, an abandoned stream) before its
+	// buffer is dropped, so a pathological client can't grow p.buffers
+	// unboundedly.
+	defaultMaxBufferAge = 2 * time.Minute
+	// recentDecisionTTL bounds how long a flushed trace's keep/drop
+	// decision is remembered, so a span that ends after its trace's root
+	// (common for detached/async child spans) is routed by that decision
+	// instead of starting a buffer that would never flush.
+	recentDecisionTTL = 30 * time.Second
+	// bufferSweepInterval controls how often stale buffers and expired
+	// decisions are swept out.
+	bufferSweepInterval = 30 * time.Second
+)
+
+// TailPolicy decides, after a trace's root span has ended, whether the
+// buffered spans of that trace should be forwarded to the exporter.
+type TailPolicy interface {
+	// ShouldKeep inspects the completed spans of a single trace and
+	// returns true if the trace should be forwarded.
+	ShouldKeep(spans []sdktrace.ReadOnlySpan) bool
+}
+
+// ErrorPolicy keeps traces that contain at least one span with an error status.
+type ErrorPolicy struct{}
+
+func (ErrorPolicy) ShouldKeep(spans []sdktrace.ReadOnlySpan) bool {
+	for _, span := range spans {
+		if span.Status().Code == codes.Error {
+			return true
+		}
+	}
+	return false
+}
+
+// LatencyThresholdPolicy keeps traces whose root span duration meets or
+// exceeds Threshold.
+type LatencyThresholdPolicy struct {
+	Threshold int64 // nanoseconds
+}
+
+func (p LatencyThresholdPolicy) ShouldKeep(spans []sdktrace.ReadOnlySpan) bool {
+	for _, span := range spans {
+		if !span.Parent().IsValid() || span.Parent().IsRemote() {
+			return span.EndTime().Sub(span.StartTime()).Nanoseconds() >= p.Threshold
+		}
+	}
+	return false
+}
+
+// StatusCodePolicy keeps traces containing a span with an http.status_code
+// attribute matching one of Codes.
+type StatusCodePolicy struct {
+	Codes []int64
+}
+
+func (p StatusCodePolicy) ShouldKeep(spans []sdktrace.ReadOnlySpan) bool {
+	for _, span := range spans {
+		for _, attr := range span.Attributes() {
+			if string(attr.Key) != "http.status_code" {
+				continue
+			}
+			for _, code := range p.Codes {
+				if attr.Value.AsInt64() == code {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// traceBuffer accumulates the spans seen for a single trace until its root
+// span ends.
+type traceBuffer struct {
+	spans     []sdktrace.ReadOnlySpan
+	rootEnded bool
+	createdAt time.Time
+}
+
+// decision records a flushed trace's keep/drop verdict so a span that ends
+// after the root (e.g. a detached async child) can be routed without
+// reopening a buffer that would never flush.
+type decision struct {
+	keep bool
+	at   time.Time
+}
+
+// tailSamplingProcessor buffers completed spans per trace and only hands
+// them to the wrapped processor once the root span ends and a TailPolicy
+// votes to keep the trace.
+type tailSamplingProcessor struct {
+	next     sdktrace.SpanProcessor
+	policies []TailPolicy
+
+	mutex   sync.Mutex
+	buffers map[trace.TraceID]*traceBuffer
+	decided map[trace.TraceID]decision
+
+	stopSweep chan struct{}
+	sweepDone sync.WaitGroup
+}
+
+// newTailSamplingProcessor wraps next (typically a batch span processor
+// bound to the OTLP exporter) with tail-based buffering. A trace is
+// forwarded if any of policies votes to keep it. A background sweep bounds
+// both p.buffers (traces whose root never ends) and p.decided (flushed
+// traces' remembered verdicts); call Shutdown to stop it.
+func newTailSamplingProcessor(next sdktrace.SpanProcessor, policies ...TailPolicy) *tailSamplingProcessor {
+	p := &tailSamplingProcessor{
+		next:      next,
+		policies:  policies,
+		buffers:   make(map[trace.TraceID]*traceBuffer),
+		decided:   make(map[trace.TraceID]decision),
+		stopSweep: make(chan struct{}),
+	}
+	p.sweepDone.Add(1)
+	go p.sweepLoop()
+	return p
+}
+
+func (p *tailSamplingProcessor) sweepLoop() {
+	defer p.sweepDone.Done()
+
+	ticker := time.NewTicker(bufferSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopSweep:
+			return
+		case <-ticker.C:
+			p.sweep()
+		}
+	}
+}
+
+// sweep drops buffers whose root has never ended after defaultMaxBufferAge
+// and forgets decisions older than recentDecisionTTL.
+func (p *tailSamplingProcessor) sweep() {
+	now := time.Now()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for traceID, buf := range p.buffers {
+		if now.Sub(buf.createdAt) > defaultMaxBufferAge {
+			delete(p.buffers, traceID)
+		}
+	}
+	for traceID, d := range p.decided {
+		if now.Sub(d.at) > recentDecisionTTL {
+			delete(p.decided, traceID)
+		}
+	}
+}
+
+func (p *tailSamplingProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	p.next.OnStart(ctx, span)
+}
+
+func (p *tailSamplingProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	traceID := span.SpanContext().TraceID()
+	// A span whose parent came from another process (e.g. an incoming
+	// traceparent header) is this process's root for the trace.
+	isRoot := !span.Parent().IsValid() || span.Parent().IsRemote()
+
+	p.mutex.Lock()
+	if d, ok := p.decided[traceID]; ok {
+		// The root for this trace already ended and was flushed; this span
+		// arrived afterward (e.g. a detached child finishing after the
+		// request that spawned it). Route it by the remembered verdict
+		// instead of starting a buffer that would never see a root again.
+		p.mutex.Unlock()
+		if d.keep {
+			p.next.OnEnd(span)
+		}
+		return
+	}
+
+	buf, ok := p.buffers[traceID]
+	if !ok {
+		buf = &traceBuffer{createdAt: time.Now()}
+		p.buffers[traceID] = buf
+	}
+	buf.spans = append(buf.spans, span)
+	if isRoot {
+		buf.rootEnded = true
+	}
+
+	var spans []sdktrace.ReadOnlySpan
+	ready := buf.rootEnded
+	if ready {
+		spans = buf.spans
+		delete(p.buffers, traceID)
+	}
+	p.mutex.Unlock()
+
+	if !ready {
+		return
+	}
+
+	keep := p.shouldKeep(spans)
+
+	p.mutex.Lock()
+	p.decided[traceID] = decision{keep: keep, at: time.Now()}
+	p.mutex.Unlock()
+
+	if keep {
+		for _, s := range spans {
+			p.next.OnEnd(s)
+		}
+	}
+}
+
+func (p *tailSamplingProcessor) shouldKeep(spans []sdktrace.ReadOnlySpan) bool {
+	if len(p.policies) == 0 {
+		return true
+	}
+	for _, policy := range p.policies {
+		if policy.ShouldKeep(spans) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	close(p.stopSweep)
+	p.sweepDone.Wait()
+	return p.next.Shutdown(ctx)
+}
+
+func (p *tailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
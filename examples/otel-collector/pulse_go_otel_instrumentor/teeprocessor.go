@@ -0,0 +1,59 @@
+package pulse_otel
+
+import (
+	"context"
+	"fmt"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// TeeSpanProcessor fans every span lifecycle event out to multiple
+// processors. PulseTraceManager builds one automatically when a project's
+// Config.Exporters configures more than one destination.
+type TeeSpanProcessor struct {
+	processors []sdktrace.SpanProcessor
+}
+
+// NewTeeSpanProcessor returns a SpanProcessor that forwards every call to
+// each of processors, in order.
+func NewTeeSpanProcessor(processors ...sdktrace.SpanProcessor) *TeeSpanProcessor {
+	return &TeeSpanProcessor{processors: processors}
+}
+
+func (t *TeeSpanProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	for _, p := range t.processors {
+		p.OnStart(ctx, span)
+	}
+}
+
+func (t *TeeSpanProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	for _, p := range t.processors {
+		p.OnEnd(span)
+	}
+}
+
+func (t *TeeSpanProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, p := range t.processors {
+		if err := p.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("tee span processor shutdown errors: %v", errs)
+	}
+	return nil
+}
+
+func (t *TeeSpanProcessor) ForceFlush(ctx context.Context) error {
+	var errs []error
+	for _, p := range t.processors {
+		if err := p.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("tee span processor flush errors: %v", errs)
+	}
+	return nil
+}